@@ -3,9 +3,11 @@ module github.com/PengShaw/GoUtilsKit
 go 1.22.2
 
 require (
-	github.com/agiledragon/gomonkey/v2 v2.2.0 // indirect
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.9.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultAsyncQueueSize is the queue size of an async [Logger] by default.
+const DefaultAsyncQueueSize = 1024
+
+// An OverflowPolicy decides what an async [Logger] does with a record when
+// its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop drops the record.
+	OverflowDrop
+	// OverflowDropCount drops the record, and writes a Warn record with the
+	// number of dropped records once there is room again.
+	OverflowDropCount
+)
+
+// Records at LevelFatal and above are never dropped, they wait until there is
+// room in the queue whatever the policy, so they are written before the exit.
+
+// AsyncOptions configures the async mode of a [Logger].
+type AsyncOptions struct {
+	// QueueSize is the number of records the queue holds, DefaultAsyncQueueSize if it is 0.
+	QueueSize int
+	// Overflow is the policy when the queue is full, OverflowBlock by default.
+	Overflow OverflowPolicy
+}
+
+// asyncEntry is a queued record with the logger writing it.
+type asyncEntry struct {
+	l *Logger
+	r Record
+}
+
+// asyncQueue is a bounded ring buffer of records drained by a goroutine.
+type asyncQueue struct {
+	overflow OverflowPolicy
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	ring []asyncEntry
+	head int
+	n    int
+	// queued and written count the records, so Flush waits for the records
+	// queued before it is called.
+	queued     uint64
+	written    uint64
+	dropped    uint64
+	unreported uint64
+	closed     bool
+	done       chan struct{}
+}
+
+func newAsyncQueue(opts AsyncOptions) *asyncQueue {
+	size := opts.QueueSize
+	if size <= 0 {
+		size = DefaultAsyncQueueSize
+	}
+	q := &asyncQueue{
+		overflow: opts.Overflow,
+		ring:     make([]asyncEntry, size),
+		done:     make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// push queues r, it returns false if the queue is closed and r must be
+// written synchronously. Records at LevelFatal and above are never dropped,
+// they wait for room whatever the overflow policy.
+func (q *asyncQueue) push(l *Logger, r *Record) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.overflow == OverflowBlock || r.Level >= LevelFatal {
+		for !q.closed && q.n == len(q.ring) {
+			q.cond.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+	if q.n == len(q.ring) {
+		if q.overflow == OverflowDropCount {
+			q.dropped++
+			q.unreported++
+		}
+		return true
+	}
+	q.ring[(q.head+q.n)%len(q.ring)] = asyncEntry{l: l, r: *r}
+	q.n++
+	q.queued++
+	q.cond.Broadcast()
+	return true
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	var batch []asyncEntry
+	for {
+		q.mu.Lock()
+		for q.n == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.n == 0 {
+			q.mu.Unlock()
+			return
+		}
+		batch = batch[:0]
+		for ; q.n > 0; q.n-- {
+			batch = append(batch, q.ring[q.head])
+			q.ring[q.head] = asyncEntry{}
+			q.head = (q.head + 1) % len(q.ring)
+		}
+		unreported := q.unreported
+		q.unreported = 0
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		for i := range batch {
+			batch[i].l.writeSync(&batch[i].r)
+		}
+		if unreported > 0 {
+			batch[len(batch)-1].l.writeSync(&Record{
+				Time:    time.Now(),
+				Level:   LevelWarn,
+				Message: "logger dropped " + strconv.FormatUint(unreported, 10) + " records",
+			})
+		}
+
+		q.mu.Lock()
+		q.written += uint64(len(batch))
+		q.cond.Broadcast()
+		q.mu.Unlock()
+		clear(batch)
+	}
+}
+
+// flush waits for the records queued before it is called to be written.
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	target := q.queued
+	for q.written < target {
+		q.cond.Wait()
+	}
+}
+
+// close stops queuing, and waits for the queued records to be written.
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	<-q.done
+}
+
+// SetAsync makes the logger write records in background. Records are queued
+// in a bounded queue, and written by a goroutine, so a slow output does not
+// block the caller. The values of fields must not be modified after they
+// are logged.
+//
+// Call [Logger.Flush] to wait for the queued records, and [Logger.Close] at
+// shutdown. Fatal flushes the logger before os.Exit. SetAsync must not be
+// called concurrently with output, the children created by [Logger.With]
+// after the call share the queue.
+func (l *Logger) SetAsync(opts AsyncOptions) {
+	l.async = newAsyncQueue(opts)
+}
+
+// SetAsync makes the standard logger write records in background.
+func SetAsync(opts AsyncOptions) {
+	std.SetAsync(opts)
+}
+
+// Dropped returns the number of records dropped by the [OverflowDropCount] policy.
+func (l *Logger) Dropped() uint64 {
+	if l.async == nil {
+		return 0
+	}
+	l.async.mu.Lock()
+	defer l.async.mu.Unlock()
+	return l.async.dropped
+}
+
+// Flush waits for the queued records to be written if the logger is async,
+// and syncs the output and sinks which have a Sync method, such as *os.File.
+func (l *Logger) Flush() error {
+	if l.async != nil {
+		l.async.flush()
+	}
+	return l.sync()
+}
+
+// Flush flushes the standard logger.
+func Flush() error {
+	return std.Flush()
+}
+
+// Close writes the summary of the sampler and the queued records, stops
+// the background goroutine, and syncs the output. Records logged after
+// Close are written synchronously.
+func (l *Logger) Close() error {
+	l.flushSummary()
+	if l.async != nil {
+		l.async.close()
+	}
+	return l.sync()
+}
+
+// Close closes the standard logger.
+func Close() error {
+	return std.Close()
+}
+
+type syncer interface {
+	Sync() error
+}
+
+// sync syncs the output, or the sinks if they are set.
+func (l *Logger) sync() error {
+	if len(l.sinks) == 0 {
+		if s, ok := l.Writer().(syncer); ok {
+			return ignoreSyncError(s.Sync())
+		}
+		return nil
+	}
+	var errs []error
+	for _, sink := range l.sinks {
+		if s, ok := sink.(syncer); ok {
+			errs = append(errs, ignoreSyncError(s.Sync()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ignoreSyncError ignores the error of syncing a terminal or pipe, such as
+// os.Stdout, which do not support it.
+func ignoreSyncError(err error) error {
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, errors.ErrUnsupported) {
+		return nil
+	}
+	return err
+}
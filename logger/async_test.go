@@ -0,0 +1,100 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+// gateWriter blocks each write until the gate is opened.
+type gateWriter struct {
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+	buf     bytes.Buffer
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+	return w.buf.Write(p)
+}
+
+func TestAsync(t *testing.T) {
+	t.Run("flush", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetAsync(logger.AsyncOptions{QueueSize: 4})
+		defer l.Close()
+
+		for i := 0; i < 100; i++ {
+			l.With("i", i).Infoln("test Infoln")
+		}
+		assert.Nil(t, l.Flush(), "should not be an error")
+		for i := 0; i < 100; i++ {
+			assert.Contains(t, got.String(), fmt.Sprintf("[INFO] test Infoln i=%d\n", i))
+		}
+	})
+
+	t.Run("drop count", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		w := &gateWriter{started: make(chan struct{}), gate: make(chan struct{})}
+		l.SetOutput(w)
+		l.SetAsync(logger.AsyncOptions{QueueSize: 1, Overflow: logger.OverflowDropCount})
+
+		l.Infoln("test first")
+		<-w.started
+		l.Infoln("test queued")
+		l.Infoln("test dropped")
+		l.Infoln("test dropped")
+		assert.Equal(t, uint64(2), l.Dropped(), "they should be equal")
+
+		close(w.gate)
+		assert.Nil(t, l.Close(), "should not be an error")
+		assert.Contains(t, w.buf.String(), "[INFO] test first\n")
+		assert.Contains(t, w.buf.String(), "[INFO] test queued\n")
+		assert.Contains(t, w.buf.String(), "[WARN] logger dropped 2 records\n")
+		assert.NotContains(t, w.buf.String(), "test dropped")
+	})
+
+	t.Run("fatal is not dropped", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		w := &gateWriter{started: make(chan struct{}), gate: make(chan struct{})}
+		l.SetOutput(w)
+		l.SetExitFunc(func(int) {})
+		l.SetAsync(logger.AsyncOptions{QueueSize: 1, Overflow: logger.OverflowDrop})
+
+		l.Infoln("test first")
+		<-w.started
+		l.Infoln("test queued")
+		done := make(chan struct{})
+		go func() {
+			l.Fatalln("test fatal")
+			close(done)
+		}()
+		// let Fatalln find the queue full
+		time.Sleep(50 * time.Millisecond)
+		close(w.gate)
+		<-done
+		assert.Contains(t, w.buf.String(), "[FATAL] test fatal\n")
+	})
+
+	t.Run("write after close", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		l.SetAsync(logger.AsyncOptions{})
+		l.Infoln("test before close")
+		assert.Nil(t, l.Close(), "should not be an error")
+		l.Infoln("test after close")
+		assert.Equal(t, "[INFO] test before close\n[INFO] test after close\n", got.String(), "they should be equal")
+	})
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// A ConsoleEncoder formats records for humans reading a terminal, with a
+// short timestamp, an aligned level colored by severity, and fields in logfmt.
+//
+//	01:23:23.000 INFO  message key=value
+//	01:23:23.000 ERROR app/main.go:12 message err="failed"
+//
+// It is meant for local development, use [NewConsoleEncoder] to enable
+// colors when the output is a terminal.
+type ConsoleEncoder struct {
+	// TimeFormat is the layout of the timestamp, "15:04:05.000" if it is
+	// empty, "-" omits the timestamp.
+	TimeFormat string
+	// Color colors the level and the keys of the fields with ANSI escape codes.
+	Color bool
+	// Caller writes the caller before the message.
+	Caller bool
+}
+
+// NewConsoleEncoder creates a [ConsoleEncoder] which colors the output if
+// [ColorEnabled] reports true for w.
+func NewConsoleEncoder(w io.Writer) ConsoleEncoder {
+	return ConsoleEncoder{Color: ColorEnabled(w)}
+}
+
+// ColorEnabled reports whether the output to w should be colored. It is
+// false if the NO_COLOR environment variable is set, true if FORCE_COLOR
+// is set, and otherwise true if w is a terminal and TERM is not "dumb".
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("FORCE_COLOR"); v != "" {
+		return v != "0" && v != "false"
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device, such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ANSI escape codes of the console encoder.
+const (
+	colorReset = "\x1b[0m"
+	colorFaint = "\x1b[2m"
+)
+
+// levelColors are the colors of the known levels.
+var levelColors = []string{
+	LevelTrace: "\x1b[90m",
+	LevelDebug: "\x1b[36m",
+	LevelInfo:  "\x1b[32m",
+	LevelWarn:  "\x1b[33m",
+	LevelError: "\x1b[31m",
+	LevelFatal: "\x1b[1;31m",
+	LevelPanic: "\x1b[1;35m",
+}
+
+// Encode implements [Encoder].
+func (e ConsoleEncoder) Encode(buf *bytes.Buffer, r *Record) error {
+	if e.TimeFormat != "-" {
+		layout := e.TimeFormat
+		if layout == "" {
+			layout = "15:04:05.000"
+		}
+		if e.Color {
+			buf.WriteString(colorFaint)
+		}
+		buf.Write(r.Time.AppendFormat(buf.AvailableBuffer(), layout))
+		if e.Color {
+			buf.WriteString(colorReset)
+		}
+		buf.WriteByte(' ')
+	}
+
+	name := strings.ToUpper(r.Level.String())
+	if e.Color && r.Level >= 0 && int(r.Level) < len(levelColors) {
+		buf.WriteString(levelColors[r.Level])
+		buf.WriteString(name)
+		buf.WriteString(colorReset)
+	} else {
+		buf.WriteString(name)
+	}
+	// align the messages after the longest level names
+	for i := len(name); i < 5; i++ {
+		buf.WriteByte(' ')
+	}
+	buf.WriteByte(' ')
+
+	if e.Caller && r.PC != 0 {
+		if e.Color {
+			buf.WriteString(colorFaint)
+		}
+		buf.WriteString(shortCaller(frame(r.PC)))
+		if e.Color {
+			buf.WriteString(colorReset)
+		}
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(r.Message)
+	fields, errStack := expandErrors(r.Fields)
+	for _, f := range fields {
+		buf.WriteByte(' ')
+		if e.Color {
+			buf.WriteString(colorFaint)
+			appendLogfmt(buf, f.Key)
+			buf.WriteString("=" + colorReset)
+		} else {
+			appendLogfmt(buf, f.Key)
+			buf.WriteByte('=')
+		}
+		appendLogfmt(buf, formatValue(f.Value))
+	}
+	buf.WriteByte('\n')
+	appendStack(buf, r.Stack)
+	appendStack(buf, errStack)
+	return nil
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+)
+
+// A ContextExtractor returns the fields of a context, such as the trace and
+// request IDs set by a middleware. It returns nil if ctx has none.
+type ContextExtractor func(ctx context.Context) []Field
+
+// AddContextExtractor adds an extractor of the fields added by
+// [Logger.WithContext] and [FromContext]. The children created by
+// [Logger.With] after the call inherit the extractor.
+//
+// AddContextExtractor must not be called concurrently with output.
+func (l *Logger) AddContextExtractor(fn ContextExtractor) {
+	l.extractors = append(l.extractors[:len(l.extractors):len(l.extractors)], fn)
+}
+
+// AddContextExtractor adds a context extractor to the standard logger.
+func AddContextExtractor(fn ContextExtractor) {
+	std.AddContextExtractor(fn)
+}
+
+// WithContext returns a child logger which adds the fields extracted from ctx
+// to each record. If l already has fields extracted by WithContext, such as
+// a logger returned by [FromContext], they are replaced in place.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	fields := l.contextFields(ctx)
+	if len(fields) == 0 && l.ctxLen == 0 {
+		return l
+	}
+	c := *l
+	if l.ctxLen == 0 {
+		c.ctxAt = len(l.fields)
+	}
+	c.fields = make([]Field, 0, len(l.fields)-l.ctxLen+len(fields))
+	c.fields = append(c.fields, l.fields[:c.ctxAt]...)
+	c.fields = append(c.fields, fields...)
+	c.fields = append(c.fields, l.fields[c.ctxAt+l.ctxLen:]...)
+	c.ctxLen = len(fields)
+	return &c
+}
+
+// contextFields returns the fields extracted from ctx.
+func (l *Logger) contextFields(ctx context.Context) []Field {
+	var fields []Field
+	for _, fn := range l.extractors {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or the standard logger if
+// it has none, with the fields extracted from ctx, see [Logger.WithContext].
+func FromContext(ctx context.Context) *Logger {
+	l, ok := ctx.Value(contextKey{}).(*Logger)
+	if !ok {
+		l = std
+	}
+	return l.WithContext(ctx)
+}
@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+type requestIDKey struct{}
+
+func requestID(ctx context.Context) []logger.Field {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return []logger.Field{logger.Any("request_id", id)}
+	}
+	return nil
+}
+
+func TestContext(t *testing.T) {
+	l := logger.New(logger.LevelInfo)
+	var got bytes.Buffer
+	l.SetOutput(&got)
+	l.SetFlags(0)
+	l.AddContextExtractor(requestID)
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "42")
+
+	t.Run("with context", func(t *testing.T) {
+		got.Reset()
+		l.WithContext(ctx).Infow("test Infow", "code", 200)
+		l.WithContext(context.Background()).Infoln("test Infoln")
+		assert.Equal(t, "[INFO] test Infow request_id=42 code=200\n[INFO] test Infoln\n", got.String(), "they should be equal")
+	})
+
+	t.Run("from context", func(t *testing.T) {
+		got.Reset()
+		ctx := logger.NewContext(ctx, l.With("service", "api"))
+		logger.FromContext(ctx).Infoln("test Infoln")
+		assert.Equal(t, "[INFO] test Infoln service=api request_id=42\n", got.String(), "they should be equal")
+		assert.Equal(t, logger.Default(), logger.FromContext(context.Background()), "they should be equal")
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		got.Reset()
+		ctx := logger.NewContext(ctx, l)
+		ctx = logger.NewContext(ctx, logger.FromContext(ctx).With("path", "/"))
+		ctx = context.WithValue(ctx, requestIDKey{}, "43")
+		logger.FromContext(ctx).Infoln("test Infoln")
+		assert.Equal(t, "[INFO] test Infoln request_id=43 path=/\n", got.String(), "they should be equal")
+	})
+
+	t.Run("slog handler", func(t *testing.T) {
+		got.Reset()
+		slog.New(logger.NewSlogHandler(l)).InfoContext(ctx, "test InfoContext")
+		assert.Equal(t, "[INFO] test InfoContext request_id=42\n", got.String(), "they should be equal")
+	})
+}
@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An Encoder formats a [Record] into buf, ending with a newline.
+type Encoder interface {
+	Encode(buf *bytes.Buffer, r *Record) error
+}
+
+// A TextEncoder formats records as the standard log package does, with
+// the "[LEVEL] " tag before the message, and fields in logfmt after it.
+//
+//	2009/01/23 01:23:23 [INFO] message key=value key2="quoted value"
+//
+// The stack trace of a record is written on the lines after it.
+type TextEncoder struct {
+	// Flags and Prefix are the same as the flags and prefix of [log.Logger].
+	Flags  int
+	Prefix string
+}
+
+// Encode implements [Encoder].
+func (e TextEncoder) Encode(buf *bytes.Buffer, r *Record) error {
+	if e.Flags&log.Lmsgprefix == 0 {
+		buf.WriteString(e.Prefix)
+	}
+	if e.Flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		t := r.Time
+		if e.Flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		b := buf.AvailableBuffer()
+		if e.Flags&log.Ldate != 0 {
+			b = t.AppendFormat(b, "2006/01/02 ")
+		}
+		if e.Flags&log.Lmicroseconds != 0 {
+			b = t.AppendFormat(b, "15:04:05.000000 ")
+		} else if e.Flags&log.Ltime != 0 {
+			b = t.AppendFormat(b, "15:04:05 ")
+		}
+		buf.Write(b)
+	}
+	if e.Flags&(log.Lshortfile|log.Llongfile) != 0 {
+		file, line := "???", 0
+		if r.PC != 0 {
+			f := frame(r.PC)
+			file, line = f.File, f.Line
+		}
+		if e.Flags&log.Lshortfile != 0 {
+			file = filepath.Base(file)
+		}
+		buf.WriteString(file)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(line))
+		buf.WriteString(": ")
+	}
+	if e.Flags&log.Lmsgprefix != 0 {
+		buf.WriteString(e.Prefix)
+	}
+
+	buf.WriteString(levelTag(r.Level))
+	buf.WriteString(r.Message)
+	fields, errStack := expandErrors(r.Fields)
+	for _, f := range fields {
+		buf.WriteByte(' ')
+		appendLogfmt(buf, f.Key)
+		buf.WriteByte('=')
+		appendLogfmt(buf, formatValue(f.Value))
+	}
+	buf.WriteByte('\n')
+	appendStack(buf, r.Stack)
+	appendStack(buf, errStack)
+	return nil
+}
+
+// A JSONEncoder formats records as JSON lines, with the time, level, message
+// and caller before the fields, and the stack trace, if any, after them.
+//
+//	{"time":"2009-01-23T01:23:23.000000001Z","level":"Info","msg":"message","caller":"app/main.go:12","key":"value"}
+//
+// The zero value uses the keys and time format of the example above.
+type JSONEncoder struct {
+	// TimeKey, LevelKey, MessageKey, CallerKey and StackKey are the keys of
+	// the record attributes, an empty key is replaced by the default, and "-"
+	// omits the attribute.
+	TimeKey    string
+	LevelKey   string
+	MessageKey string
+	CallerKey  string
+	StackKey   string
+	// TimeFormat is a layout for time.Format, or one of TimeFormatUnix,
+	// TimeFormatUnixMilli and TimeFormatUnixNano for numeric timestamps.
+	// The default is time.RFC3339Nano.
+	TimeFormat string
+}
+
+// Numeric timestamp formats of [JSONEncoder].
+const (
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unixmilli"
+	TimeFormatUnixNano  = "unixnano"
+)
+
+// Encode implements [Encoder].
+func (e JSONEncoder) Encode(buf *bytes.Buffer, r *Record) error {
+	buf.WriteByte('{')
+	sep := false
+	key := func(k, def string) bool {
+		if k == "" {
+			k = def
+		}
+		if k == "-" {
+			return false
+		}
+		if sep {
+			buf.WriteByte(',')
+		}
+		sep = true
+		appendJSON(buf, k)
+		buf.WriteByte(':')
+		return true
+	}
+
+	if key(e.TimeKey, "time") {
+		switch e.TimeFormat {
+		case "":
+			appendJSON(buf, r.Time.Format(time.RFC3339Nano))
+		case TimeFormatUnix:
+			buf.WriteString(strconv.FormatInt(r.Time.Unix(), 10))
+		case TimeFormatUnixMilli:
+			buf.WriteString(strconv.FormatInt(r.Time.UnixMilli(), 10))
+		case TimeFormatUnixNano:
+			buf.WriteString(strconv.FormatInt(r.Time.UnixNano(), 10))
+		default:
+			appendJSON(buf, r.Time.Format(e.TimeFormat))
+		}
+	}
+	if key(e.LevelKey, "level") {
+		appendJSON(buf, r.Level.String())
+	}
+	if key(e.MessageKey, "msg") {
+		appendJSON(buf, r.Message)
+	}
+	if r.PC != 0 && key(e.CallerKey, "caller") {
+		appendJSON(buf, shortCaller(frame(r.PC)))
+	}
+	for _, f := range r.Fields {
+		if sep {
+			buf.WriteByte(',')
+		}
+		sep = true
+		appendJSON(buf, f.Key)
+		buf.WriteByte(':')
+		appendJSON(buf, f.Value)
+	}
+	if r.Stack != "" && key(e.StackKey, "stack") {
+		appendJSON(buf, r.Stack)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// appendStack writes the stack trace s, if any, ending with a newline.
+func appendStack(buf *bytes.Buffer, s string) {
+	if s == "" {
+		return
+	}
+	buf.WriteString(s)
+	if !strings.HasSuffix(s, "\n") {
+		buf.WriteByte('\n')
+	}
+}
+
+// levelTags caches the "[LEVEL] " tags of the known levels.
+var levelTags = func() []string {
+	tags := make([]string, LevelPanic+1)
+	for l := range tags {
+		tags[l] = "[" + strings.ToUpper(LogLevel(l).String()) + "] "
+	}
+	return tags
+}()
+
+func levelTag(level LogLevel) string {
+	if level >= 0 && int(level) < len(levelTags) {
+		return levelTags[level]
+	}
+	return "[" + strings.ToUpper(level.String()) + "] "
+}
+
+// frame returns the stack frame of pc.
+func frame(pc uintptr) runtime.Frame {
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return f
+}
+
+// shortCaller formats f as "dir/file.go:line".
+func shortCaller(f runtime.Frame) string {
+	file := f.File
+	if i := strings.LastIndexByte(file, '/'); i > 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(f.Line)
+}
+
+// formatValue formats v as text.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// appendLogfmt writes s, quoted if it is empty or contains spaces, quotes,
+// equal signs or non-printable characters.
+func appendLogfmt(buf *bytes.Buffer, s string) {
+	quote := s == ""
+	for _, c := range s {
+		if c <= ' ' || c == '=' || c == '"' || c == utf8.RuneError || !unicode.IsPrint(c) {
+			quote = true
+			break
+		}
+	}
+	if quote {
+		buf.Write(strconv.AppendQuote(buf.AvailableBuffer(), s))
+		return
+	}
+	buf.WriteString(s)
+}
+
+// appendJSON writes v as JSON, the errors of [Err] are written as objects,
+// other errors and values which fail to marshal are written as strings.
+func appendJSON(buf *bytes.Buffer, v any) {
+	switch x := v.(type) {
+	case errorValue:
+		appendErrorJSON(buf, x.inspect())
+		return
+	case error:
+		v = x.Error()
+	case []byte:
+		v = string(x)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	buf.Write(b)
+}
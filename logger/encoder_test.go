@@ -0,0 +1,116 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestTextEncoder(t *testing.T) {
+	r := &logger.Record{
+		Time:    time.Date(2009, 1, 23, 1, 23, 23, 123456789, time.UTC),
+		Level:   logger.LevelInfo,
+		Message: "test message",
+		Fields:  []logger.Field{logger.Any("err", errors.New("some error")), logger.Any("n", 1)},
+	}
+	tests := []struct {
+		enc  logger.TextEncoder
+		want string
+	}{
+		{logger.TextEncoder{}, `[INFO] test message err="some error" n=1` + "\n"},
+		{logger.TextEncoder{Flags: log.LstdFlags, Prefix: "app: "}, `app: 2009/01/23 01:23:23 [INFO] test message err="some error" n=1` + "\n"},
+		{logger.TextEncoder{Flags: log.Lmicroseconds | log.Lmsgprefix, Prefix: "app: "}, `01:23:23.123456 app: [INFO] test message err="some error" n=1` + "\n"},
+		{logger.TextEncoder{Flags: log.Lshortfile}, `???:0: [INFO] test message err="some error" n=1` + "\n"},
+	}
+	for _, tt := range tests {
+		var got bytes.Buffer
+		assert.NoError(t, tt.enc.Encode(&got, r), "should not be an error")
+		assert.Equal(t, tt.want, got.String(), "they should be equal")
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	l := logger.New(logger.LevelInfo)
+	var got bytes.Buffer
+	l.SetOutput(&got)
+	l.SetEncoder(logger.JSONEncoder{})
+	l.With("service", "api").Infow("test Infow", "err", errors.New("some error"), "n", 1)
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal(got.Bytes(), &m), "should not be an error")
+	assert.Equal(t, "Info", m["level"], "they should be equal")
+	assert.Equal(t, "test Infow", m["msg"], "they should be equal")
+	assert.Equal(t, "api", m["service"], "they should be equal")
+	assert.Equal(t, "some error", m["err"], "they should be equal")
+	assert.Equal(t, float64(1), m["n"], "they should be equal")
+	assert.Contains(t, m, "time")
+	assert.Contains(t, m["caller"], "logger/encoder_test.go:")
+}
+
+func TestJSONEncoderConfig(t *testing.T) {
+	r := &logger.Record{
+		Time:    time.Date(2009, 1, 23, 1, 23, 23, 0, time.UTC),
+		Level:   logger.LevelWarn,
+		Message: "test message",
+		Fields:  []logger.Field{logger.Any("n", 1)},
+	}
+	tests := []struct {
+		enc  logger.JSONEncoder
+		want string
+	}{
+		{logger.JSONEncoder{}, `{"time":"2009-01-23T01:23:23Z","level":"Warn","msg":"test message","n":1}` + "\n"},
+		{logger.JSONEncoder{TimeKey: "ts", LevelKey: "severity", MessageKey: "message", TimeFormat: logger.TimeFormatUnix},
+			`{"ts":1232673803,"severity":"Warn","message":"test message","n":1}` + "\n"},
+		{logger.JSONEncoder{TimeKey: "-", TimeFormat: time.Kitchen}, `{"level":"Warn","msg":"test message","n":1}` + "\n"},
+		{logger.JSONEncoder{TimeFormat: time.Kitchen, LevelKey: "-", MessageKey: "-"}, `{"time":"1:23AM","n":1}` + "\n"},
+	}
+	for _, tt := range tests {
+		var got bytes.Buffer
+		assert.NoError(t, tt.enc.Encode(&got, r), "should not be an error")
+		assert.Equal(t, tt.want, got.String(), "they should be equal")
+	}
+}
+
+func TestConsoleEncoder(t *testing.T) {
+	r := &logger.Record{
+		Time:    time.Date(2009, 1, 23, 1, 23, 23, 123456789, time.UTC),
+		Level:   logger.LevelInfo,
+		Message: "test message",
+		Fields:  []logger.Field{logger.Any("err", errors.New("some error")), logger.Any("n", 1)},
+	}
+	tests := []struct {
+		enc  logger.ConsoleEncoder
+		want string
+	}{
+		{logger.ConsoleEncoder{}, `01:23:23.123 INFO  test message err="some error" n=1` + "\n"},
+		{logger.ConsoleEncoder{TimeFormat: "-"}, `INFO  test message err="some error" n=1` + "\n"},
+		{logger.ConsoleEncoder{TimeFormat: "-", Color: true},
+			"\x1b[32mINFO\x1b[0m  test message \x1b[2merr=\x1b[0m\"some error\" \x1b[2mn=\x1b[0m1\n"},
+	}
+	for _, tt := range tests {
+		var got bytes.Buffer
+		assert.NoError(t, tt.enc.Encode(&got, r), "should not be an error")
+		assert.Equal(t, tt.want, got.String(), "they should be equal")
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	assert.False(t, logger.ColorEnabled(&bytes.Buffer{}), "should not color a buffer")
+
+	t.Setenv("FORCE_COLOR", "1")
+	assert.True(t, logger.ColorEnabled(&bytes.Buffer{}), "should be forced")
+	assert.True(t, logger.NewConsoleEncoder(&bytes.Buffer{}).Color, "should be forced")
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, logger.ColorEnabled(os.Stderr), "should be disabled by NO_COLOR")
+}
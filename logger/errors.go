@@ -0,0 +1,256 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// maxErrorDepth bounds the expansion of error chains.
+const maxErrorDepth = 32
+
+// An ErrorFielder is an error which carries fields, such as the IDs of a
+// failed request. [Err] adds its fields to the record.
+type ErrorFielder interface {
+	error
+	ErrorFields() []Field
+}
+
+// A StackTracer is an error which carries the stack trace of where it is
+// created. [Err] adds its stack trace to the record.
+type StackTracer interface {
+	error
+	StackTrace() string
+}
+
+// Err returns a [Field] with key "error" for err, which expands the chain of
+// err. The encoders write the fields of the errors in the chain which are
+// [ErrorFielder], and the stack trace of the first [StackTracer]. The
+// [JSONEncoder] writes err as a nested object with its causes.
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+// NamedErr is like [Err] with key.
+func NamedErr(key string, err error) Field {
+	if err == nil {
+		return Field{Key: key, Value: nil}
+	}
+	return Field{Key: key, Value: errorValue{err: err}}
+}
+
+// errorValue is the value of a field returned by Err.
+type errorValue struct {
+	err error
+	// info, if it is set, replaces the chain of err, such as when it is redacted.
+	info *errorInfo
+}
+
+func (e errorValue) Error() string {
+	if e.info != nil {
+		return e.info.msg
+	}
+	return e.err.Error()
+}
+
+func (e errorValue) Unwrap() error { return e.err }
+
+// inspect returns the chain of the error.
+func (e errorValue) inspect() errorInfo {
+	if e.info != nil {
+		return *e.info
+	}
+	return inspectError(e.err, 0)
+}
+
+// LogValue implements slog.LogValuer, it returns a group of the message,
+// fields and stack trace.
+func (e errorValue) LogValue() slog.Value {
+	info := e.inspect()
+	attrs := []slog.Attr{slog.String("msg", info.msg)}
+	for _, f := range info.allFields() {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	if stack := info.firstStack(); stack != "" {
+		attrs = append(attrs, slog.String("stack", stack))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// errorInfo is an error of a chain, with the causes it wraps.
+type errorInfo struct {
+	msg    string
+	fields []Field
+	stack  string
+	causes []errorInfo
+}
+
+func inspectError(err error, depth int) errorInfo {
+	info := errorInfo{msg: err.Error()}
+	if e, ok := err.(ErrorFielder); ok {
+		info.fields = e.ErrorFields()
+	}
+	if e, ok := err.(StackTracer); ok {
+		info.stack = e.StackTrace()
+	}
+	if depth >= maxErrorDepth {
+		return info
+	}
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			causes = []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	}
+	for _, cause := range causes {
+		if cause != nil {
+			info.causes = append(info.causes, inspectError(cause, depth+1))
+		}
+	}
+	// fold a wrapper with the message of its cause, such as the errors of
+	// ErrorWithFields and ErrorWithStack, into one error
+	if len(info.causes) == 1 && info.causes[0].msg == info.msg {
+		c := info.causes[0]
+		info.fields = append(info.fields[:len(info.fields):len(info.fields)], c.fields...)
+		if info.stack == "" {
+			info.stack = c.stack
+		}
+		info.causes = c.causes
+	}
+	return info
+}
+
+// allFields returns the fields of the errors in the chain, outermost first.
+func (info errorInfo) allFields() []Field {
+	fields := info.fields
+	for _, c := range info.causes {
+		fields = append(fields[:len(fields):len(fields)], c.allFields()...)
+	}
+	return fields
+}
+
+// firstStack returns the stack trace of the outermost error which has one.
+func (info errorInfo) firstStack() string {
+	if info.stack != "" {
+		return info.stack
+	}
+	for _, c := range info.causes {
+		if stack := c.firstStack(); stack != "" {
+			return stack
+		}
+	}
+	return ""
+}
+
+// appendErrorJSON writes info as a JSON object.
+func appendErrorJSON(buf *bytes.Buffer, info errorInfo) {
+	buf.WriteString(`{"msg":`)
+	appendJSON(buf, info.msg)
+	if len(info.fields) > 0 {
+		buf.WriteString(`,"fields":{`)
+		for i, f := range info.fields {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			appendJSON(buf, f.Key)
+			buf.WriteByte(':')
+			appendJSON(buf, f.Value)
+		}
+		buf.WriteByte('}')
+	}
+	if info.stack != "" {
+		buf.WriteString(`,"stack":`)
+		appendJSON(buf, info.stack)
+	}
+	if len(info.causes) > 0 {
+		buf.WriteString(`,"causes":[`)
+		for i, c := range info.causes {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			appendErrorJSON(buf, c)
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+}
+
+// expandErrors replaces the fields of [Err] by their message, followed by
+// the fields of their chain keyed by "key.field", for the text encoders.
+// It returns the stack traces of the errors.
+func expandErrors(fields []Field) ([]Field, string) {
+	i := 0
+	for ; i < len(fields); i++ {
+		if _, ok := fields[i].Value.(errorValue); ok {
+			break
+		}
+	}
+	if i == len(fields) {
+		return fields, ""
+	}
+
+	expanded := append(make([]Field, 0, len(fields)+4), fields[:i]...)
+	var stack string
+	for _, f := range fields[i:] {
+		ev, ok := f.Value.(errorValue)
+		if !ok {
+			expanded = append(expanded, f)
+			continue
+		}
+		info := ev.inspect()
+		expanded = append(expanded, Field{Key: f.Key, Value: info.msg})
+		for _, ef := range info.allFields() {
+			expanded = append(expanded, Field{Key: f.Key + "." + ef.Key, Value: ef.Value})
+		}
+		stack += info.firstStack()
+	}
+	return expanded, stack
+}
+
+// ErrorWithFields returns an error wrapping err, which carries the
+// alternating key/value pairs as fields, see [ErrorFielder].
+func ErrorWithFields(err error, kv ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{err: err, fields: appendFields(nil, kv)}
+}
+
+type fieldsError struct {
+	err    error
+	fields []Field
+}
+
+func (e *fieldsError) Error() string        { return e.err.Error() }
+func (e *fieldsError) Unwrap() error        { return e.err }
+func (e *fieldsError) ErrorFields() []Field { return e.fields }
+
+// ErrorWithStack returns an error wrapping err, which carries the stack
+// trace of the caller, see [StackTracer].
+func ErrorWithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	// skip runtime.Callers and ErrorWithStack
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return &stackError{err: err, pcs: pcs[:n:n]}
+}
+
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string { return e.err.Error() }
+func (e *stackError) Unwrap() error { return e.err }
+
+func (e *stackError) StackTrace() string {
+	var b strings.Builder
+	appendFrames(&b, runtime.CallersFrames(e.pcs))
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestErr(t *testing.T) {
+	base := logger.ErrorWithFields(errors.New("connection refused"), "addr", "10.0.0.1:80")
+	err := fmt.Errorf("dial: %w", errors.Join(base, logger.ErrorWithFields(errors.New("timeout"), "after", 3)))
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		rec := &logger.Record{Level: logger.LevelInfo, Message: "failed", Fields: []logger.Field{logger.Err(err), logger.Any("n", 1)}}
+		logger.TextEncoder{}.Encode(&buf, rec)
+		assert.Equal(t, "[INFO] failed error=\"dial: connection refused\\ntimeout\" error.addr=10.0.0.1:80 error.after=3 n=1\n",
+			buf.String(), "they should be equal")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		rec := &logger.Record{Level: logger.LevelInfo, Message: "failed", Fields: []logger.Field{logger.Err(err)}}
+		logger.JSONEncoder{TimeKey: "-"}.Encode(&buf, rec)
+		var got map[string]any
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &got), "should not be an error")
+		assert.Equal(t, map[string]any{
+			"msg": "dial: connection refused\ntimeout",
+			"causes": []any{map[string]any{
+				"msg": "connection refused\ntimeout",
+				"causes": []any{
+					map[string]any{
+						"msg":    "connection refused",
+						"fields": map[string]any{"addr": "10.0.0.1:80"},
+					},
+					map[string]any{
+						"msg":    "timeout",
+						"fields": map[string]any{"after": float64(3)},
+					},
+				},
+			}},
+		}, got["error"], "they should be equal")
+	})
+
+	t.Run("stack", func(t *testing.T) {
+		var buf bytes.Buffer
+		rec := &logger.Record{Level: logger.LevelInfo, Message: "failed", Fields: []logger.Field{
+			logger.Err(fmt.Errorf("open: %w", logger.ErrorWithStack(errors.New("not found")))),
+		}}
+		logger.TextEncoder{}.Encode(&buf, rec)
+		lines := strings.Split(buf.String(), "\n")
+		assert.Equal(t, `[INFO] failed error="open: not found"`, lines[0], "they should be equal")
+		assert.True(t, strings.HasPrefix(lines[1], "github.com/PengShaw/GoUtilsKit/logger_test.TestErr"), "should start with the caller")
+		assert.Contains(t, lines[2], "errors_test.go:", "should contain the file")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Equal(t, logger.Any("error", nil), logger.Err(nil), "they should be equal")
+		assert.Nil(t, logger.ErrorWithFields(nil, "k", "v"), "should be nil")
+		assert.Nil(t, logger.ErrorWithStack(nil), "should be nil")
+	})
+
+	t.Run("is", func(t *testing.T) {
+		assert.ErrorIs(t, logger.Err(err).Value.(error), base, "should wrap the error")
+	})
+
+	t.Run("redact", func(t *testing.T) {
+		r := logger.NewRedactor(logger.RedactOptions{Rules: logger.DefaultRedactRules(), Fields: []string{"token"}})
+		f := logger.Err(logger.ErrorWithFields(errors.New("login jane@example.com"), "token", "secret"))
+		rec := &logger.Record{Level: logger.LevelInfo, Message: "failed", Fields: []logger.Field{f}}
+		r.Redact(rec, nil)
+		var buf bytes.Buffer
+		logger.TextEncoder{}.Encode(&buf, rec)
+		assert.Equal(t, "[INFO] failed error=\"login ***\" error.token=***\n", buf.String(), "they should be equal")
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		l.Info("failed", "error", logger.Err(base).Value)
+		assert.Equal(t, "level=INFO msg=failed error.msg=\"connection refused\" error.addr=10.0.0.1:80\n", buf.String(), "they should be equal")
+	})
+}
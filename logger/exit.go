@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"os"
+)
+
+// A PanicError is the value of the panic raised by Panic, Panicf, Panicln
+// and Panicw when [Logger.SetPanicError] is enabled. It carries the record.
+type PanicError struct {
+	Record Record
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return "[PANIC] " + e.Record.Message
+}
+
+// SetExitFunc sets the function called by Fatal, Fatalf, Fatalln and Fatalw
+// with exit code 1, os.Exit by default. A function which returns, such as
+// in tests, makes them return to their caller.
+func (l *Logger) SetExitFunc(fn func(code int)) {
+	l.exitFunc = fn
+}
+
+// SetExitFunc sets the exit function of the standard logger.
+func SetExitFunc(fn func(code int)) {
+	std.SetExitFunc(fn)
+}
+
+// OnFatal adds a function called by Fatal, Fatalf, Fatalln and Fatalw before
+// the exit function, such as closing files and flushing sinks. The functions
+// run in the order they are added, after the logger is closed, see [Logger.Close].
+func (l *Logger) OnFatal(fn func()) {
+	l.onFatal = append(l.onFatal[:len(l.onFatal):len(l.onFatal)], fn)
+}
+
+// OnFatal adds a function called by the Fatal functions of the standard logger.
+func OnFatal(fn func()) {
+	std.OnFatal(fn)
+}
+
+// SetPanicError makes Panic, Panicf, Panicln and Panicw raise a *[PanicError]
+// carrying the record if enabled, instead of the "[PANIC] " prefixed message.
+func (l *Logger) SetPanicError(enabled bool) {
+	l.panicError = enabled
+}
+
+// SetPanicError sets the panic mode of the standard logger.
+func SetPanicError(enabled bool) {
+	std.SetPanicError(enabled)
+}
+
+// fatal closes the logger, runs the OnFatal functions and calls the exit function.
+func (l *Logger) fatal() {
+	l.Close()
+	for _, fn := range l.onFatal {
+		fn()
+	}
+	exit := l.exitFunc
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(1)
+}
+
+// panic flushes the logger, and panics with the message s or a *PanicError of r.
+func (l *Logger) panic(s string, r *Record) {
+	l.Flush()
+	if l.panicError {
+		panic(&PanicError{Record: *r})
+	}
+	panic("[PANIC] " + s)
+}
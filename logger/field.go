@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"time"
+)
+
+// A Field is a key/value pair attached to a log record.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Any returns a [Field] for key and value.
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// A Record is a log entry passed from a [Logger] to its [Encoder].
+type Record struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  []Field
+	// PC is the program counter of the caller, it is zero if unknown.
+	PC uintptr
+	// Stack is the stack trace of the caller, it is empty unless the
+	// level of the record is at or above the stack trace level of the logger.
+	Stack string
+}
+
+// badKey is the key used when a key/value pair has no string key.
+const badKey = "!BADKEY"
+
+// appendFields parses alternating key/value pairs into fields. An element
+// which is already a Field is used as is, a key without value, or a value
+// which is not preceded by a string key, is keyed by "!BADKEY".
+func appendFields(fields []Field, kv []any) []Field {
+	for len(kv) > 0 {
+		switch k := kv[0].(type) {
+		case Field:
+			fields = append(fields, k)
+			kv = kv[1:]
+		case string:
+			if len(kv) == 1 {
+				fields = append(fields, Field{Key: badKey, Value: k})
+				kv = kv[1:]
+				continue
+			}
+			fields = append(fields, Field{Key: k, Value: kv[1]})
+			kv = kv[2:]
+		default:
+			fields = append(fields, Field{Key: badKey, Value: k})
+			kv = kv[1:]
+		}
+	}
+	return fields
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"bytes"
+	"go/format"
 	"os"
 	"strings"
 	"text/template"
@@ -11,77 +12,152 @@ import (
 	"github.com/PengShaw/GoUtilsKit/templater"
 )
 
+var header = `// Code generated by gen.go; DO NOT EDIT.
+
+package logger
+
+import (
+	"fmt"
+)
+`
+
 var text1 = `
 // {{ .Name }}f record {{ .Name }} log followed by a call to {{ .Followed }}.
+// {{ .Note }}
 func (l *Logger) {{ .Name }}f(format string, v ...any) {
-	if l.level <= Level{{ .Name }} {
-		l.Logger.{{ .Name }}f("[{{ .Name | ToUpper }}] "+format, v...)
+	if l.enabled(Level{{ .Name }}) {
+		s := fmt.Sprintf(format, v...)
+		{{ if .Record }}r := {{ end }}l.output(Level{{ .Name }}, "", s, nil)
+		l.{{ .Then }}
 	}
 }
 
 // {{ .Name }}ln record {{ .Name }} log followed by a call to {{ .Followed }}.
+// {{ .Note }}
 func (l *Logger) {{ .Name }}ln(v ...any) {
-	if l.level <= Level{{ .Name }} {
-		v := append([]any{"[{{ .Name | ToUpper }}]"}, v...)
-		l.Logger.{{ .Name }}ln(v...)
+	if l.enabled(Level{{ .Name }}) {
+		s := fmt.Sprintln(v...)
+		{{ if .Record }}r := {{ end }}l.output(Level{{ .Name }}, "", s, nil)
+		l.{{ .Then }}
 	}
 }
 
 // {{ .Name }} record {{ .Name }} log followed by a call to {{ .Followed }}.
+// {{ .Note }}
 func (l *Logger) {{ .Name }}(v ...any) {
-	if l.level <= Level{{ .Name }} {
-		v := append([]any{"[{{ .Name | ToUpper }}] "}, v...)
-		l.Logger.{{ .Name }}(v...)
+	if l.enabled(Level{{ .Name }}) {
+		s := fmt.Sprint(v...)
+		{{ if .Record }}r := {{ end }}l.output(Level{{ .Name }}, "", s, nil)
+		l.{{ .Then }}
+	}
+}
+
+// {{ .Name }}w record {{ .Name }} log with alternating key/value pairs followed by a call to {{ .Followed }}.
+// {{ .Note }}
+func (l *Logger) {{ .Name }}w(msg string, kv ...any) {
+	if l.enabled(Level{{ .Name }}) {
+		s := msg
+		{{ if .Record }}r := {{ end }}l.output(Level{{ .Name }}, s, s, kv)
+		l.{{ .Then }}
 	}
 }
 
 // {{ .Name }}f record {{ .Name }} log followed by a call to {{ .Followed }}.
+// {{ .Note }}
 func {{ .Name }}f(format string, v ...any) {
-	std.{{ .Name }}f(format, v...)
+	if std.enabled(Level{{ .Name }}) {
+		s := fmt.Sprintf(format, v...)
+		{{ if .Record }}r := {{ end }}std.output(Level{{ .Name }}, "", s, nil)
+		std.{{ .Then }}
+	}
 }
 
 // {{ .Name }}ln record {{ .Name }} log followed by a call to {{ .Followed }}.
+// {{ .Note }}
 func {{ .Name }}ln(v ...any) {
-	std.{{ .Name }}ln( v...)
+	if std.enabled(Level{{ .Name }}) {
+		s := fmt.Sprintln(v...)
+		{{ if .Record }}r := {{ end }}std.output(Level{{ .Name }}, "", s, nil)
+		std.{{ .Then }}
+	}
 }
 
 // {{ .Name }} record {{ .Name }} log followed by a call to {{ .Followed }}.
+// {{ .Note }}
 func {{ .Name }}(v ...any) {
-	std.{{ .Name }}(v...)
+	if std.enabled(Level{{ .Name }}) {
+		s := fmt.Sprint(v...)
+		{{ if .Record }}r := {{ end }}std.output(Level{{ .Name }}, "", s, nil)
+		std.{{ .Then }}
+	}
+}
+
+// {{ .Name }}w record {{ .Name }} log with alternating key/value pairs followed by a call to {{ .Followed }}.
+// {{ .Note }}
+func {{ .Name }}w(msg string, kv ...any) {
+	if std.enabled(Level{{ .Name }}) {
+		s := msg
+		{{ if .Record }}r := {{ end }}std.output(Level{{ .Name }}, s, s, kv)
+		std.{{ .Then }}
+	}
 }
 `
 
 var text2 = `
 // {{ .Name }}f record {{ .Name }} log.
 func (l *Logger) {{ .Name }}f(format string, v ...any) {
-	l.printf(Level{{ .Name }}, "[{{ .Name | ToUpper }}] "+format, v...)
+	if l.enabled(Level{{ .Name }}) {
+		l.output(Level{{ .Name }}, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // {{ .Name }}ln record {{ .Name }} log.
 func (l *Logger) {{ .Name }}ln(v ...any) {
-	v = append([]any{"[{{ .Name | ToUpper }}]"}, v...)
-	l.println(Level{{ .Name }}, v...)
+	if l.enabled(Level{{ .Name }}) {
+		l.output(Level{{ .Name }}, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // {{ .Name }} record {{ .Name }} log.
 func (l *Logger) {{ .Name }}(v ...any) {
-	v = append([]any{"[{{ .Name | ToUpper }}] "}, v...)
-	l.print(Level{{ .Name }}, v...)
+	if l.enabled(Level{{ .Name }}) {
+		l.output(Level{{ .Name }}, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// {{ .Name }}w record {{ .Name }} log with alternating key/value pairs.
+func (l *Logger) {{ .Name }}w(msg string, kv ...any) {
+	if l.enabled(Level{{ .Name }}) {
+		l.output(Level{{ .Name }}, msg, msg, kv)
+	}
 }
 
 // {{ .Name }}f record {{ .Name }} log.
 func {{ .Name }}f(format string, v ...any) {
-	std.{{ .Name }}f(format, v...)
+	if std.enabled(Level{{ .Name }}) {
+		std.output(Level{{ .Name }}, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // {{ .Name }}ln record {{ .Name }} log.
 func {{ .Name }}ln(v ...any) {
-	std.{{ .Name }}ln(v...)
+	if std.enabled(Level{{ .Name }}) {
+		std.output(Level{{ .Name }}, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // {{ .Name }} record {{ .Name }} log.
 func {{ .Name }}(v ...any) {
-	std.{{ .Name }}(v...)
+	if std.enabled(Level{{ .Name }}) {
+		std.output(Level{{ .Name }}, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// {{ .Name }}w record {{ .Name }} log with alternating key/value pairs.
+func {{ .Name }}w(msg string, kv ...any) {
+	if std.enabled(Level{{ .Name }}) {
+		std.output(Level{{ .Name }}, msg, msg, kv)
+	}
 }
 `
 
@@ -101,7 +177,7 @@ func writeFile(filename string, b []byte) {
 
 func main() {
 	var texts = []string{
-		"// Code generated by gen.go; DO NOT EDIT.\n\npackage logger \n",
+		header,
 		text1,
 		text1,
 		text2,
@@ -115,11 +191,17 @@ func main() {
 		struct {
 			Name     string
 			Followed string
-		}{"Panic", "panic()"},
+			Then     string
+			Note     string
+			Record   bool
+		}{"Panic", "panic()", "panic(s, &r)", "The logger is flushed first, see [Logger.SetPanicError].", true},
 		struct {
 			Name     string
 			Followed string
-		}{"Fatal", "os.Exit(1)"},
+			Then     string
+			Note     string
+			Record   bool
+		}{"Fatal", "os.Exit(1)", "fatal()", "The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].", false},
 		struct {
 			Name string
 		}{"Error"},
@@ -147,5 +229,7 @@ func main() {
 		result.Write(r)
 	}
 
-	writeFile("generated_logger.go", result.Bytes())
+	src, err := format.Source(result.Bytes())
+	check(err)
+	writeFile("generated_logger.go", src)
 }
@@ -1,239 +1,447 @@
 // Code generated by gen.go; DO NOT EDIT.
 
-package logger 
+package logger
+
+import (
+	"fmt"
+)
 
 // Panicf record Panic log followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
 func (l *Logger) Panicf(format string, v ...any) {
-	if l.level <= LevelPanic {
-		l.Logger.Panicf("[PANIC] "+format, v...)
+	if l.enabled(LevelPanic) {
+		s := fmt.Sprintf(format, v...)
+		r := l.output(LevelPanic, "", s, nil)
+		l.panic(s, &r)
 	}
 }
 
 // Panicln record Panic log followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
 func (l *Logger) Panicln(v ...any) {
-	if l.level <= LevelPanic {
-		v := append([]any{"[PANIC]"}, v...)
-		l.Logger.Panicln(v...)
+	if l.enabled(LevelPanic) {
+		s := fmt.Sprintln(v...)
+		r := l.output(LevelPanic, "", s, nil)
+		l.panic(s, &r)
 	}
 }
 
 // Panic record Panic log followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
 func (l *Logger) Panic(v ...any) {
-	if l.level <= LevelPanic {
-		v := append([]any{"[PANIC] "}, v...)
-		l.Logger.Panic(v...)
+	if l.enabled(LevelPanic) {
+		s := fmt.Sprint(v...)
+		r := l.output(LevelPanic, "", s, nil)
+		l.panic(s, &r)
+	}
+}
+
+// Panicw record Panic log with alternating key/value pairs followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
+func (l *Logger) Panicw(msg string, kv ...any) {
+	if l.enabled(LevelPanic) {
+		s := msg
+		r := l.output(LevelPanic, s, s, kv)
+		l.panic(s, &r)
 	}
 }
 
 // Panicf record Panic log followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
 func Panicf(format string, v ...any) {
-	std.Panicf(format, v...)
+	if std.enabled(LevelPanic) {
+		s := fmt.Sprintf(format, v...)
+		r := std.output(LevelPanic, "", s, nil)
+		std.panic(s, &r)
+	}
 }
 
 // Panicln record Panic log followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
 func Panicln(v ...any) {
-	std.Panicln( v...)
+	if std.enabled(LevelPanic) {
+		s := fmt.Sprintln(v...)
+		r := std.output(LevelPanic, "", s, nil)
+		std.panic(s, &r)
+	}
 }
 
 // Panic record Panic log followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
 func Panic(v ...any) {
-	std.Panic(v...)
+	if std.enabled(LevelPanic) {
+		s := fmt.Sprint(v...)
+		r := std.output(LevelPanic, "", s, nil)
+		std.panic(s, &r)
+	}
+}
+
+// Panicw record Panic log with alternating key/value pairs followed by a call to panic().
+// The logger is flushed first, see [Logger.SetPanicError].
+func Panicw(msg string, kv ...any) {
+	if std.enabled(LevelPanic) {
+		s := msg
+		r := std.output(LevelPanic, s, s, kv)
+		std.panic(s, &r)
+	}
 }
 
 // Fatalf record Fatal log followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
 func (l *Logger) Fatalf(format string, v ...any) {
-	if l.level <= LevelFatal {
-		l.Logger.Fatalf("[FATAL] "+format, v...)
+	if l.enabled(LevelFatal) {
+		s := fmt.Sprintf(format, v...)
+		l.output(LevelFatal, "", s, nil)
+		l.fatal()
 	}
 }
 
 // Fatalln record Fatal log followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
 func (l *Logger) Fatalln(v ...any) {
-	if l.level <= LevelFatal {
-		v := append([]any{"[FATAL]"}, v...)
-		l.Logger.Fatalln(v...)
+	if l.enabled(LevelFatal) {
+		s := fmt.Sprintln(v...)
+		l.output(LevelFatal, "", s, nil)
+		l.fatal()
 	}
 }
 
 // Fatal record Fatal log followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
 func (l *Logger) Fatal(v ...any) {
-	if l.level <= LevelFatal {
-		v := append([]any{"[FATAL] "}, v...)
-		l.Logger.Fatal(v...)
+	if l.enabled(LevelFatal) {
+		s := fmt.Sprint(v...)
+		l.output(LevelFatal, "", s, nil)
+		l.fatal()
+	}
+}
+
+// Fatalw record Fatal log with alternating key/value pairs followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
+func (l *Logger) Fatalw(msg string, kv ...any) {
+	if l.enabled(LevelFatal) {
+		s := msg
+		l.output(LevelFatal, s, s, kv)
+		l.fatal()
 	}
 }
 
 // Fatalf record Fatal log followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
 func Fatalf(format string, v ...any) {
-	std.Fatalf(format, v...)
+	if std.enabled(LevelFatal) {
+		s := fmt.Sprintf(format, v...)
+		std.output(LevelFatal, "", s, nil)
+		std.fatal()
+	}
 }
 
 // Fatalln record Fatal log followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
 func Fatalln(v ...any) {
-	std.Fatalln( v...)
+	if std.enabled(LevelFatal) {
+		s := fmt.Sprintln(v...)
+		std.output(LevelFatal, "", s, nil)
+		std.fatal()
+	}
 }
 
 // Fatal record Fatal log followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
 func Fatal(v ...any) {
-	std.Fatal(v...)
+	if std.enabled(LevelFatal) {
+		s := fmt.Sprint(v...)
+		std.output(LevelFatal, "", s, nil)
+		std.fatal()
+	}
+}
+
+// Fatalw record Fatal log with alternating key/value pairs followed by a call to os.Exit(1).
+// The logger is closed and the OnFatal functions run first, see [Logger.SetExitFunc].
+func Fatalw(msg string, kv ...any) {
+	if std.enabled(LevelFatal) {
+		s := msg
+		std.output(LevelFatal, s, s, kv)
+		std.fatal()
+	}
 }
 
 // Errorf record Error log.
 func (l *Logger) Errorf(format string, v ...any) {
-	l.printf(LevelError, "[ERROR] "+format, v...)
+	if l.enabled(LevelError) {
+		l.output(LevelError, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Errorln record Error log.
 func (l *Logger) Errorln(v ...any) {
-	v = append([]any{"[ERROR]"}, v...)
-	l.println(LevelError, v...)
+	if l.enabled(LevelError) {
+		l.output(LevelError, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Error record Error log.
 func (l *Logger) Error(v ...any) {
-	v = append([]any{"[ERROR] "}, v...)
-	l.print(LevelError, v...)
+	if l.enabled(LevelError) {
+		l.output(LevelError, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Errorw record Error log with alternating key/value pairs.
+func (l *Logger) Errorw(msg string, kv ...any) {
+	if l.enabled(LevelError) {
+		l.output(LevelError, msg, msg, kv)
+	}
 }
 
 // Errorf record Error log.
 func Errorf(format string, v ...any) {
-	std.Errorf(format, v...)
+	if std.enabled(LevelError) {
+		std.output(LevelError, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Errorln record Error log.
 func Errorln(v ...any) {
-	std.Errorln(v...)
+	if std.enabled(LevelError) {
+		std.output(LevelError, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Error record Error log.
 func Error(v ...any) {
-	std.Error(v...)
+	if std.enabled(LevelError) {
+		std.output(LevelError, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Errorw record Error log with alternating key/value pairs.
+func Errorw(msg string, kv ...any) {
+	if std.enabled(LevelError) {
+		std.output(LevelError, msg, msg, kv)
+	}
 }
 
 // Warnf record Warn log.
 func (l *Logger) Warnf(format string, v ...any) {
-	l.printf(LevelWarn, "[WARN] "+format, v...)
+	if l.enabled(LevelWarn) {
+		l.output(LevelWarn, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Warnln record Warn log.
 func (l *Logger) Warnln(v ...any) {
-	v = append([]any{"[WARN]"}, v...)
-	l.println(LevelWarn, v...)
+	if l.enabled(LevelWarn) {
+		l.output(LevelWarn, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Warn record Warn log.
 func (l *Logger) Warn(v ...any) {
-	v = append([]any{"[WARN] "}, v...)
-	l.print(LevelWarn, v...)
+	if l.enabled(LevelWarn) {
+		l.output(LevelWarn, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Warnw record Warn log with alternating key/value pairs.
+func (l *Logger) Warnw(msg string, kv ...any) {
+	if l.enabled(LevelWarn) {
+		l.output(LevelWarn, msg, msg, kv)
+	}
 }
 
 // Warnf record Warn log.
 func Warnf(format string, v ...any) {
-	std.Warnf(format, v...)
+	if std.enabled(LevelWarn) {
+		std.output(LevelWarn, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Warnln record Warn log.
 func Warnln(v ...any) {
-	std.Warnln(v...)
+	if std.enabled(LevelWarn) {
+		std.output(LevelWarn, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Warn record Warn log.
 func Warn(v ...any) {
-	std.Warn(v...)
+	if std.enabled(LevelWarn) {
+		std.output(LevelWarn, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Warnw record Warn log with alternating key/value pairs.
+func Warnw(msg string, kv ...any) {
+	if std.enabled(LevelWarn) {
+		std.output(LevelWarn, msg, msg, kv)
+	}
 }
 
 // Infof record Info log.
 func (l *Logger) Infof(format string, v ...any) {
-	l.printf(LevelInfo, "[INFO] "+format, v...)
+	if l.enabled(LevelInfo) {
+		l.output(LevelInfo, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Infoln record Info log.
 func (l *Logger) Infoln(v ...any) {
-	v = append([]any{"[INFO]"}, v...)
-	l.println(LevelInfo, v...)
+	if l.enabled(LevelInfo) {
+		l.output(LevelInfo, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Info record Info log.
 func (l *Logger) Info(v ...any) {
-	v = append([]any{"[INFO] "}, v...)
-	l.print(LevelInfo, v...)
+	if l.enabled(LevelInfo) {
+		l.output(LevelInfo, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Infow record Info log with alternating key/value pairs.
+func (l *Logger) Infow(msg string, kv ...any) {
+	if l.enabled(LevelInfo) {
+		l.output(LevelInfo, msg, msg, kv)
+	}
 }
 
 // Infof record Info log.
 func Infof(format string, v ...any) {
-	std.Infof(format, v...)
+	if std.enabled(LevelInfo) {
+		std.output(LevelInfo, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Infoln record Info log.
 func Infoln(v ...any) {
-	std.Infoln(v...)
+	if std.enabled(LevelInfo) {
+		std.output(LevelInfo, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Info record Info log.
 func Info(v ...any) {
-	std.Info(v...)
+	if std.enabled(LevelInfo) {
+		std.output(LevelInfo, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Infow record Info log with alternating key/value pairs.
+func Infow(msg string, kv ...any) {
+	if std.enabled(LevelInfo) {
+		std.output(LevelInfo, msg, msg, kv)
+	}
 }
 
 // Debugf record Debug log.
 func (l *Logger) Debugf(format string, v ...any) {
-	l.printf(LevelDebug, "[DEBUG] "+format, v...)
+	if l.enabled(LevelDebug) {
+		l.output(LevelDebug, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Debugln record Debug log.
 func (l *Logger) Debugln(v ...any) {
-	v = append([]any{"[DEBUG]"}, v...)
-	l.println(LevelDebug, v...)
+	if l.enabled(LevelDebug) {
+		l.output(LevelDebug, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Debug record Debug log.
 func (l *Logger) Debug(v ...any) {
-	v = append([]any{"[DEBUG] "}, v...)
-	l.print(LevelDebug, v...)
+	if l.enabled(LevelDebug) {
+		l.output(LevelDebug, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Debugw record Debug log with alternating key/value pairs.
+func (l *Logger) Debugw(msg string, kv ...any) {
+	if l.enabled(LevelDebug) {
+		l.output(LevelDebug, msg, msg, kv)
+	}
 }
 
 // Debugf record Debug log.
 func Debugf(format string, v ...any) {
-	std.Debugf(format, v...)
+	if std.enabled(LevelDebug) {
+		std.output(LevelDebug, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Debugln record Debug log.
 func Debugln(v ...any) {
-	std.Debugln(v...)
+	if std.enabled(LevelDebug) {
+		std.output(LevelDebug, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Debug record Debug log.
 func Debug(v ...any) {
-	std.Debug(v...)
+	if std.enabled(LevelDebug) {
+		std.output(LevelDebug, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Debugw record Debug log with alternating key/value pairs.
+func Debugw(msg string, kv ...any) {
+	if std.enabled(LevelDebug) {
+		std.output(LevelDebug, msg, msg, kv)
+	}
 }
 
 // Tracef record Trace log.
 func (l *Logger) Tracef(format string, v ...any) {
-	l.printf(LevelTrace, "[TRACE] "+format, v...)
+	if l.enabled(LevelTrace) {
+		l.output(LevelTrace, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Traceln record Trace log.
 func (l *Logger) Traceln(v ...any) {
-	v = append([]any{"[TRACE]"}, v...)
-	l.println(LevelTrace, v...)
+	if l.enabled(LevelTrace) {
+		l.output(LevelTrace, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Trace record Trace log.
 func (l *Logger) Trace(v ...any) {
-	v = append([]any{"[TRACE] "}, v...)
-	l.print(LevelTrace, v...)
+	if l.enabled(LevelTrace) {
+		l.output(LevelTrace, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Tracew record Trace log with alternating key/value pairs.
+func (l *Logger) Tracew(msg string, kv ...any) {
+	if l.enabled(LevelTrace) {
+		l.output(LevelTrace, msg, msg, kv)
+	}
 }
 
 // Tracef record Trace log.
 func Tracef(format string, v ...any) {
-	std.Tracef(format, v...)
+	if std.enabled(LevelTrace) {
+		std.output(LevelTrace, format, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Traceln record Trace log.
 func Traceln(v ...any) {
-	std.Traceln(v...)
+	if std.enabled(LevelTrace) {
+		std.output(LevelTrace, "", fmt.Sprintln(v...), nil)
+	}
 }
 
 // Trace record Trace log.
 func Trace(v ...any) {
-	std.Trace(v...)
+	if std.enabled(LevelTrace) {
+		std.output(LevelTrace, "", fmt.Sprint(v...), nil)
+	}
+}
+
+// Tracew record Trace log with alternating key/value pairs.
+func Tracew(msg string, kv ...any) {
+	if std.enabled(LevelTrace) {
+		std.output(LevelTrace, msg, msg, kv)
+	}
 }
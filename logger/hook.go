@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"slices"
+)
+
+// A Hook intercepts the records of a [Logger] before they are written. It
+// may modify the record, such as adding or redacting fields, or cause side
+// effects, such as counting errors. Fire returns false to drop the record.
+//
+// Hooks are called synchronously by the goroutine logging the record.
+type Hook interface {
+	Fire(r *Record) bool
+}
+
+// HookFunc is an adapter to use a function as a [Hook].
+type HookFunc func(r *Record) bool
+
+// Fire implements [Hook].
+func (f HookFunc) Fire(r *Record) bool {
+	return f(r)
+}
+
+type levelHook struct {
+	level LogLevel
+	hook  Hook
+}
+
+// AddHook adds a hook called for each record at or above level. Hooks are
+// called in the order they are added, until one drops the record. The
+// children created by [Logger.With] after the call inherit the hook.
+//
+// AddHook must not be called concurrently with output.
+func (l *Logger) AddHook(level LogLevel, h Hook) {
+	l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], levelHook{level: level, hook: h})
+}
+
+// AddHook adds a hook to the standard logger.
+func AddHook(level LogLevel, h Hook) {
+	std.AddHook(level, h)
+}
+
+// fireHooks calls the hooks for r, it returns false if a hook drops r.
+func (l *Logger) fireHooks(r *Record) bool {
+	if len(l.hooks) == 0 {
+		return true
+	}
+	// the fields may be shared with the logger, so hooks may modify them
+	r.Fields = slices.Clone(r.Fields)
+	for _, h := range l.hooks {
+		if r.Level >= h.level && !h.hook.Fire(r) {
+			return false
+		}
+	}
+	return true
+}
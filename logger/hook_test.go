@@ -0,0 +1,62 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestHooks(t *testing.T) {
+	l := logger.New(logger.LevelInfo)
+	var got bytes.Buffer
+	l.SetOutput(&got)
+	l.SetFlags(0)
+
+	errors := 0
+	l.AddHook(logger.LevelTrace, logger.HookFunc(func(r *logger.Record) bool {
+		r.Fields = append(r.Fields, logger.Any("host", "web-1"))
+		return true
+	}))
+	l.AddHook(logger.LevelTrace, logger.HookFunc(func(r *logger.Record) bool {
+		return !strings.Contains(r.Message, "password")
+	}))
+	l.AddHook(logger.LevelError, logger.HookFunc(func(r *logger.Record) bool {
+		errors++
+		return true
+	}))
+
+	t.Run("run in order", func(t *testing.T) {
+		got.Reset()
+		l.Infow("test Infow", "code", 200)
+		l.Infoln("test password")
+		l.Errorln("test Errorln")
+		l.Errorln("test Errorln password")
+		assert.Equal(t, "[INFO] test Infow code=200 host=web-1\n[ERROR] test Errorln host=web-1\n",
+			got.String(), "they should be equal")
+		// the dropped record does not reach the error hook
+		assert.Equal(t, 1, errors, "they should be equal")
+	})
+
+	t.Run("modify fields", func(t *testing.T) {
+		got.Reset()
+		child := l.With("token", "abc")
+		child.AddHook(logger.LevelTrace, logger.HookFunc(func(r *logger.Record) bool {
+			for i := range r.Fields {
+				if r.Fields[i].Key == "token" {
+					r.Fields[i].Value = "***"
+				}
+			}
+			return true
+		}))
+		child.Infoln("test child")
+		child.Infoln("test child again")
+		l.Infoln("test parent")
+		assert.Equal(t, "[INFO] test child token=*** host=web-1\n"+
+			"[INFO] test child again token=*** host=web-1\n"+
+			"[INFO] test parent host=web-1\n", got.String(), "they should be equal")
+	})
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// LevelHandler returns an http.Handler to view and change the level of l at
+// runtime, a nil l is the standard logger.
+//
+// GET responds the level as JSON, {"level":"Info"}, or as text if the request
+// accepts text/plain. PUT and POST set the level from a JSON body of the same
+// form, a "level" form value, or a text body, and respond the new level.
+// An unknown level name is answered by 400 Bad Request.
+func LevelHandler(l *Logger) http.Handler {
+	if l == nil {
+		l = std
+	}
+	return &levelHandler{l: l}
+}
+
+type levelHandler struct {
+	l *Logger
+}
+
+type levelPayload struct {
+	Level string `json:"level,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		name, err := requestLevel(r)
+		if err != nil {
+			h.reply(w, r, http.StatusBadRequest, levelPayload{Error: err.Error()})
+			return
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			h.reply(w, r, http.StatusBadRequest, levelPayload{Error: err.Error()})
+			return
+		}
+		h.l.SetLevel(level)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		h.reply(w, r, http.StatusMethodNotAllowed, levelPayload{Error: "method not allowed"})
+		return
+	}
+	h.reply(w, r, http.StatusOK, levelPayload{Level: h.l.Level()})
+}
+
+// requestLevel reads the level name from the body or form of r.
+func requestLevel(r *http.Request) (string, error) {
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch ct {
+	case "application/json":
+		var p levelPayload
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1024)).Decode(&p); err != nil {
+			return "", err
+		}
+		return p.Level, nil
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		return r.FormValue("level"), nil
+	}
+	if name := r.URL.Query().Get("level"); name != "" {
+		return name, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(r.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func (h *levelHandler) reply(w http.ResponseWriter, r *http.Request, code int, p levelPayload) {
+	if strings.Contains(r.Header.Get("Accept"), "text/plain") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if p.Error != "" {
+			io.WriteString(w, p.Error+"\n")
+		} else {
+			io.WriteString(w, p.Level+"\n")
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestLevelHandler(t *testing.T) {
+	l := logger.New(logger.LevelInfo)
+	h := logger.LevelHandler(l)
+	do := func(method, body, contentType, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/log/level", strings.NewReader(body))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodGet, "", "", "")
+	assert.Equal(t, http.StatusOK, w.Code, "they should be equal")
+	assert.JSONEq(t, `{"level":"Info"}`, w.Body.String(), "they should be equal")
+
+	w = do(http.MethodGet, "", "", "text/plain")
+	assert.Equal(t, "Info\n", w.Body.String(), "they should be equal")
+
+	w = do(http.MethodPut, `{"level":"debug"}`, "application/json", "")
+	assert.Equal(t, http.StatusOK, w.Code, "they should be equal")
+	assert.JSONEq(t, `{"level":"Debug"}`, w.Body.String(), "they should be equal")
+	assert.Equal(t, "Debug", l.Level(), "they should be equal")
+
+	w = do(http.MethodPost, url.Values{"level": {"TRACE"}}.Encode(), "application/x-www-form-urlencoded", "")
+	assert.Equal(t, http.StatusOK, w.Code, "they should be equal")
+	assert.Equal(t, "Trace", l.Level(), "they should be equal")
+
+	w = do(http.MethodPut, "error\n", "text/plain", "text/plain")
+	assert.Equal(t, "Error\n", w.Body.String(), "they should be equal")
+	assert.Equal(t, "Error", l.Level(), "they should be equal")
+
+	w = do(http.MethodPut, "verbose", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "they should be equal")
+	assert.Contains(t, w.Body.String(), `unknown level \"verbose\"`)
+	assert.Equal(t, "Error", l.Level(), "level should not be changed")
+
+	w = do(http.MethodDelete, "", "", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code, "they should be equal")
+}
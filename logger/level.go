@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// An AtomicLevel is a LogLevel which is safe to change while loggers read it.
+// Loggers sharing an AtomicLevel change their level together.
+type AtomicLevel struct {
+	v atomic.Int64
+	// parent is the level of the parent of a named logger, which is used
+	// while inherit is true.
+	parent  *AtomicLevel
+	inherit atomic.Bool
+	// children are the levels of the named children, guarded by namedMu.
+	children map[string]*AtomicLevel
+}
+
+// NewAtomicLevel creates a new *[AtomicLevel] set to level.
+func NewAtomicLevel(level LogLevel) *AtomicLevel {
+	a := new(AtomicLevel)
+	a.SetLevel(level)
+	return a
+}
+
+// Level returns the level.
+func (a *AtomicLevel) Level() LogLevel {
+	if a.inherit.Load() {
+		return a.parent.Level()
+	}
+	return LogLevel(a.v.Load())
+}
+
+// SetLevel sets the level, it overrides the level inherited by a named logger.
+func (a *AtomicLevel) SetLevel(level LogLevel) {
+	a.v.Store(int64(level))
+	a.inherit.Store(false)
+}
+
+// String returns the name of the level.
+func (a *AtomicLevel) String() string {
+	return a.Level().String()
+}
+
+// levelAliases maps lower-case alternative names to levels.
+var levelAliases = map[string]LogLevel{
+	"warning":  LevelWarn,
+	"err":      LevelError,
+	"crit":     LevelFatal,
+	"critical": LevelFatal,
+}
+
+// levelNames lists the names of the known levels.
+func levelNames() string {
+	names := make([]string, 0, LevelPanic+1)
+	for l := LevelTrace; l <= LevelPanic; l++ {
+		names = append(names, l.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseLevel returns the level of a case-insensitive level name, as returned
+// by LogLevel.String. The aliases "warning", "err", "crit" and "critical" are accepted.
+func ParseLevel(name string) (LogLevel, error) {
+	for l := LevelTrace; l <= LevelPanic; l++ {
+		if strings.EqualFold(name, l.String()) {
+			return l, nil
+		}
+	}
+	if l, ok := levelAliases[strings.ToLower(name)]; ok {
+		return l, nil
+	}
+	return 0, fmt.Errorf("logger: unknown level %q, want one of %s", name, levelNames())
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (l LogLevel) MarshalText() ([]byte, error) {
+	if l < LevelTrace || l > LevelPanic {
+		return nil, fmt.Errorf("logger: invalid level %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], see [ParseLevel].
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler], the level is a JSON string.
+func (l LogLevel) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It accepts a level name,
+// or the number of a level as marshaled before LogLevel was a JSON string.
+func (l *LogLevel) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return l.UnmarshalText([]byte(name))
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("logger: invalid level %s", data)
+	}
+	if LogLevel(n) < LevelTrace || LogLevel(n) > LevelPanic {
+		return fmt.Errorf("logger: invalid level %d", n)
+	}
+	*l = LogLevel(n)
+	return nil
+}
+
+// Set implements [flag.Value], see [ParseLevel].
+func (l *LogLevel) Set(name string) error {
+	return l.UnmarshalText([]byte(name))
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (a *AtomicLevel) MarshalText() ([]byte, error) {
+	return a.Level().MarshalText()
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], see [ParseLevel].
+func (a *AtomicLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	a.SetLevel(level)
+	return nil
+}
+
+// Set implements [flag.Value], see [ParseLevel].
+func (a *AtomicLevel) Set(name string) error {
+	return a.UnmarshalText([]byte(name))
+}
@@ -0,0 +1,135 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestAtomicLevel(t *testing.T) {
+	t.Run("test shared level", func(t *testing.T) {
+		level := logger.NewAtomicLevel(logger.LevelInfo)
+		l1 := logger.NewWithAtomicLevel(level)
+		l2 := logger.NewWithAtomicLevel(level)
+		var got bytes.Buffer
+		l1.SetOutput(&got)
+		l2.SetOutput(&got)
+
+		l1.Debugln("test Debugln 1")
+		l2.SetLevel(logger.LevelDebug)
+		assert.Equal(t, "Debug", l1.Level(), "they should be equal")
+		assert.Equal(t, level, l1.With("k", "v").AtomicLevel(), "they should be equal")
+		l1.Debugln("test Debugln 2")
+		assert.NotContains(t, got.String(), "test Debugln 1")
+		assert.Contains(t, got.String(), "[DEBUG] test Debugln 2\n")
+	})
+
+	t.Run("test concurrent changes", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		l.SetOutput(io.Discard)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					l.SetLevel(logger.LogLevel(j % 7))
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					l.Infof("test Infof %d", j)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]logger.LogLevel{
+		"trace":   logger.LevelTrace,
+		"DEBUG":   logger.LevelDebug,
+		"Info":    logger.LevelInfo,
+		"warn":    logger.LevelWarn,
+		"Warning": logger.LevelWarn,
+		"err":     logger.LevelError,
+		"error":   logger.LevelError,
+		"fatal":   logger.LevelFatal,
+		"panic":   logger.LevelPanic,
+	}
+	for name, want := range tests {
+		got, err := logger.ParseLevel(name)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, want, got, "they should be equal")
+	}
+
+	// Level returns a name which can be parsed
+	l := logger.New(logger.LevelWarn)
+	got, err := logger.ParseLevel(l.Level())
+	assert.NoError(t, err, "should not be an error")
+	assert.Equal(t, logger.LevelWarn, got, "they should be equal")
+
+	_, err = logger.ParseLevel("verbose")
+	assert.EqualError(t, err, `logger: unknown level "verbose", want one of Trace, Debug, Info, Warn, Error, Fatal, Panic`)
+}
+
+func TestLogLevelMarshal(t *testing.T) {
+	type config struct {
+		Level  logger.LogLevel     `json:"level" yaml:"level"`
+		Shared *logger.AtomicLevel `json:"shared" yaml:"shared"`
+	}
+
+	t.Run("test json", func(t *testing.T) {
+		var c config
+		err := json.Unmarshal([]byte(`{"level":"warning","shared":"debug"}`), &c)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, logger.LevelWarn, c.Level, "they should be equal")
+		assert.Equal(t, logger.LevelDebug, c.Shared.Level(), "they should be equal")
+
+		b, err := json.Marshal(c)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, `{"level":"Warn","shared":"Debug"}`, string(b), "they should be equal")
+
+		// numbers are still accepted
+		assert.NoError(t, json.Unmarshal([]byte(`{"level":4}`), &c), "should not be an error")
+		assert.Equal(t, logger.LevelError, c.Level, "they should be equal")
+		assert.Error(t, json.Unmarshal([]byte(`{"level":"verbose"}`), &c), "should be an error")
+		_, err = json.Marshal(logger.LogLevel(7))
+		assert.Error(t, err, "should be an error")
+	})
+
+	t.Run("test yaml", func(t *testing.T) {
+		var c config
+		err := yaml.Unmarshal([]byte("level: ERR\nshared: trace\n"), &c)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, logger.LevelError, c.Level, "they should be equal")
+		assert.Equal(t, logger.LevelTrace, c.Shared.Level(), "they should be equal")
+
+		b, err := yaml.Marshal(c)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, "level: Error\nshared: Trace\n", string(b), "they should be equal")
+	})
+
+	t.Run("test flag", func(t *testing.T) {
+		level := logger.LevelInfo
+		shared := logger.NewAtomicLevel(logger.LevelInfo)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&level, "level", "log level")
+		fs.Var(shared, "shared", "shared log level")
+		assert.NoError(t, fs.Parse([]string{"-level", "debug", "-shared", "fatal"}), "should not be an error")
+		assert.Equal(t, logger.LevelDebug, level, "they should be equal")
+		assert.Equal(t, logger.LevelFatal, shared.Level(), "they should be equal")
+		assert.Error(t, fs.Parse([]string{"-level", "verbose"}), "should be an error")
+	})
+}
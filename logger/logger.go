@@ -1,8 +1,16 @@
 package logger
 
 import (
+	"bytes"
+	"io"
 	"log"
+	"math"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 //go:generate stringer -type=LogLevel -trimprefix=Level
@@ -20,27 +28,77 @@ const (
 )
 
 // A Logger extend log.Logger with LogLevel.
+//
+// The embedded log.Logger holds the output, flags and prefix of the Logger.
 type Logger struct {
 	*log.Logger
-	level LogLevel
+	level   *AtomicLevel
+	name    string
+	fields  []Field
+	encoder Encoder
+	// sinks replace the encoder and output if they are set.
+	sinks []Sink
+	// async queues records to be written in background if it is set.
+	async *asyncQueue
+	// extractors add the fields of a context, fields[ctxAt:ctxAt+ctxLen]
+	// are the fields extracted by the last WithContext.
+	extractors []ContextExtractor
+	ctxAt      int
+	ctxLen     int
+	// hooks intercept the records before they are written.
+	hooks []levelHook
+	// redactor removes sensitive data from the records if it is set,
+	// except the rules and fields in redactAllow.
+	redactor    *Redactor
+	redactAllow []string
+	// exitFunc replaces os.Exit, onFatal run before it.
+	exitFunc func(code int)
+	onFatal  []func()
+	// panicError makes Panic raise a *PanicError.
+	panicError bool
+	// sampler drops the records beyond the sampling and rate limits if it is set.
+	sampler *sampler
+	// callerSkip is the number of extra frames to skip to find the caller.
+	callerSkip int
+	// stackLevel is the lowest level of records carrying a stack trace.
+	stackLevel LogLevel
+	// mu serializes writes to the output, it is shared by the children of a Logger.
+	mu *sync.Mutex
 }
 
 // New creates a new *[Logger].
 func New(level LogLevel) *Logger {
+	return NewWithAtomicLevel(NewAtomicLevel(level))
+}
+
+// NewWithAtomicLevel creates a new *[Logger] which shares level, so changing
+// level changes the output level of every logger using it.
+func NewWithAtomicLevel(level *AtomicLevel) *Logger {
 	return &Logger{
-		Logger: log.New(os.Stdout, "", log.LstdFlags),
-		level:  level,
+		Logger:     log.New(os.Stdout, "", log.LstdFlags),
+		level:      level,
+		stackLevel: noStacktrace,
+		mu:         new(sync.Mutex),
 	}
 }
 
+// NewNop creates a new *[Logger] which discards every record, even at
+// LevelFatal and LevelPanic, so it neither exits nor panics.
+func NewNop() *Logger {
+	l := New(LevelPanic + 1)
+	l.SetOutput(io.Discard)
+	return l
+}
+
 var std = New(LevelInfo)
 
 // Default returns the standard logger used by the package-level output functions.
 func Default() *Logger { return std }
 
-// SetLevel sets the output level for the logger.
+// SetLevel sets the output level for the logger. It is safe to call
+// while the logger is used, and changes every logger sharing its level.
 func (l *Logger) SetLevel(level LogLevel) {
-	l.level = level
+	l.level.SetLevel(level)
 }
 
 // Level returns the output level for the logger.
@@ -48,33 +106,206 @@ func (l *Logger) Level() string {
 	return l.level.String()
 }
 
+// AtomicLevel returns the output level for the logger, it can be shared
+// with [NewWithAtomicLevel].
+func (l *Logger) AtomicLevel() *AtomicLevel {
+	return l.level
+}
+
 // SetLevel sets the output level for the standard logger.
 func SetLevel(level LogLevel) {
-	std.level = level
+	std.SetLevel(level)
 }
 
 // Level returns the output level for the standard logger.
 func Level() string {
-	return std.level.String()
+	return std.Level()
+}
+
+// SetEncoder sets the encoder for the logger. A nil encoder, the default,
+// is a [TextEncoder] with the flags and prefix of the logger.
+func (l *Logger) SetEncoder(enc Encoder) {
+	l.encoder = enc
+}
+
+// SetEncoder sets the encoder for the standard logger.
+func SetEncoder(enc Encoder) {
+	std.SetEncoder(enc)
+}
+
+// With returns a child logger which adds the alternating key/value pairs to
+// each record. The child shares the output and level of l, and copies its encoder.
+func (l *Logger) With(kv ...any) *Logger {
+	c := *l
+	c.fields = appendFields(l.fields[:len(l.fields):len(l.fields)], kv)
+	return &c
+}
+
+// With returns a child of the standard logger which adds the alternating
+// key/value pairs to each record.
+func With(kv ...any) *Logger {
+	return std.With(kv...)
+}
+
+// AddCallerSkip returns a child logger which skips n more frames to find
+// the caller, so helper functions wrapping the logger report their callers.
+func (l *Logger) AddCallerSkip(n int) *Logger {
+	c := *l
+	c.callerSkip += n
+	return &c
+}
+
+// AddCallerSkip returns a child of the standard logger which skips n more
+// frames to find the caller.
+func AddCallerSkip(n int) *Logger {
+	return std.AddCallerSkip(n)
+}
+
+// noStacktrace is the stack trace level which disables stack traces.
+const noStacktrace = LogLevel(math.MaxInt)
+
+// SetStacktraceLevel makes the records at or above level, such as
+// LevelError, carry the stack trace of the caller. Stack traces are
+// disabled by default.
+func (l *Logger) SetStacktraceLevel(level LogLevel) {
+	l.stackLevel = level
+}
+
+// DisableStacktrace disables the stack traces set by [Logger.SetStacktraceLevel].
+func (l *Logger) DisableStacktrace() {
+	l.stackLevel = noStacktrace
+}
+
+// SetStacktraceLevel sets the stack trace level of the standard logger.
+func SetStacktraceLevel(level LogLevel) {
+	std.SetStacktraceLevel(level)
 }
 
-// print by LogLevel
+// output by LogLevel
 
-func (l *Logger) printf(level LogLevel, format string, v ...any) {
-	if l.level <= level {
-		l.Logger.Printf(format, v...)
+func (l *Logger) enabled(level LogLevel) bool {
+	if l.level.Level() > level {
+		return false
 	}
+	if len(l.sinks) == 0 {
+		return true
+	}
+	for _, s := range l.sinks {
+		if s.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
 }
 
-func (l *Logger) println(level LogLevel, v ...any) {
-	if l.level <= level {
-		l.Logger.Println(v...)
+// output writes a record of msg and the alternating key/value pairs, and
+// returns it, or the zero Record if it is dropped by the sampler.
+// tmpl is the message template which keys the sampler, msg is used if it is empty.
+// It must be called directly by the exported output methods and functions,
+// so the caller is found at the same depth.
+func (l *Logger) output(level LogLevel, tmpl, msg string, kv []any) Record {
+	if tmpl == "" {
+		tmpl = msg
+	}
+	if !l.sample(level, tmpl) {
+		return Record{}
+	}
+	r := Record{
+		Time:    time.Now(),
+		Level:   level,
+		Message: strings.TrimSuffix(msg, "\n"),
+		Fields:  l.fields,
+	}
+	if len(kv) > 0 {
+		r.Fields = appendFields(l.fields[:len(l.fields):len(l.fields)], kv)
+	}
+	// skip runtime.Callers, output and the output method
+	skip := 3 + l.callerSkip
+	var pcs [1]uintptr
+	runtime.Callers(skip, pcs[:])
+	r.PC = pcs[0]
+	if level >= l.stackLevel {
+		r.Stack = stacktrace(skip)
+	}
+	l.write(&r)
+	return r
+}
+
+// write redacts r, calls the hooks, and writes it, or queues it if the
+// logger is async. The fields added by the hooks are redacted too.
+func (l *Logger) write(r *Record) {
+	if l.redactor != nil {
+		l.redactor.Redact(r, l.redactAllow)
+	}
+	if !l.fireHooks(r) {
+		return
+	}
+	if l.redactor != nil && len(l.hooks) > 0 {
+		l.redactor.Redact(r, l.redactAllow)
 	}
+	if l.async != nil && l.async.push(l, r) {
+		return
+	}
+	l.writeSync(r)
+}
+
+// writeSync encodes r to the output, or writes it to the sinks.
+func (l *Logger) writeSync(r *Record) {
+	if len(l.sinks) > 0 {
+		l.writeSinks(r)
+		return
+	}
+
+	enc := l.encoder
+	if enc == nil {
+		enc = TextEncoder{Flags: l.Flags(), Prefix: l.Prefix()}
+	}
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	buf.Reset()
+	if err := enc.Encode(buf, r); err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Writer().Write(buf.Bytes())
+}
+
+// stacktrace returns the stack trace of the goroutine formatted as the stack
+// of a panic, skip is the argument of runtime.Callers in the caller of stacktrace.
+func stacktrace(skip int) string {
+	var b strings.Builder
+	// the first line of runtime.Stack is "goroutine N [running]:"
+	header := make([]byte, 64)
+	header = header[:runtime.Stack(header, false)]
+	if i := bytes.IndexByte(header, '\n'); i >= 0 {
+		b.Write(header[:i+1])
+	}
+
+	pcs := make([]uintptr, 64)
+	// skip stacktrace itself
+	appendFrames(&b, runtime.CallersFrames(pcs[:runtime.Callers(skip+1, pcs)]))
+	return b.String()
 }
 
-func (l *Logger) print(level LogLevel, v ...any) {
-	if l.level <= level {
-		l.Logger.Print(v...)
+// appendFrames writes frames as "function()\n\tfile:line" lines.
+func appendFrames(b *strings.Builder, frames *runtime.Frames) {
+	for {
+		f, more := frames.Next()
+		b.WriteString(f.Function)
+		b.WriteString("()\n\t")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.Line))
+		b.WriteByte('\n')
+		if !more {
+			break
+		}
 	}
 }
 
@@ -2,11 +2,13 @@ package logger_test
 
 import (
 	"bytes"
-	"os"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/PengShaw/GoUtilsKit/logger"
-	"github.com/agiledragon/gomonkey/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,18 +75,32 @@ func TestLogPanic(t *testing.T) {
 		logger.Fatalln("Fatalln")
 		assert.NotContains(t, got.String(), "Fatalln\n")
 	})
+
+	t.Run("test panic error", func(t *testing.T) {
+		l := logger.New(logger.LevelPanic)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetPanicError(true)
+		defer func() {
+			err, ok := recover().(*logger.PanicError)
+			assert.True(t, ok, "should panic with a *PanicError")
+			assert.Equal(t, "[PANIC] test Panicw", err.Error(), "they should be equal")
+			assert.Equal(t, logger.LevelPanic, err.Record.Level, "they should be equal")
+			assert.Equal(t, []logger.Field{logger.Any("code", 1)}, err.Record.Fields, "they should be equal")
+		}()
+		l.Panicw("test Panicw", "code", 1)
+	})
 }
 
 func TestLogFatal(t *testing.T) {
 
 	t.Run("test a new logger", func(t *testing.T) {
 		count := 0
-		patches := gomonkey.ApplyFunc(os.Exit, func(code int) {
+		l := logger.New(logger.LevelFatal)
+		l.SetExitFunc(func(code int) {
+			assert.Equal(t, 1, code, "they should be equal")
 			count += 1
 		})
-		defer patches.Reset()
-
-		l := logger.New(logger.LevelFatal)
 		var got bytes.Buffer
 		l.SetOutput(&got)
 		assert.Equal(t, "Fatal", l.Level(), "they should be equal")
@@ -103,10 +119,10 @@ func TestLogFatal(t *testing.T) {
 
 	t.Run("test std logger", func(t *testing.T) {
 		count := 0
-		patches := gomonkey.ApplyFunc(os.Exit, func(code int) {
+		logger.SetExitFunc(func(code int) {
 			count += 1
 		})
-		defer patches.Reset()
+		defer logger.SetExitFunc(nil)
 
 		l := logger.Default()
 		var got bytes.Buffer
@@ -126,6 +142,23 @@ func TestLogFatal(t *testing.T) {
 		logger.Errorln("Errorln")
 		assert.NotContains(t, got.String(), "Errorln\n")
 	})
+
+	t.Run("test on fatal", func(t *testing.T) {
+		var calls []string
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetAsync(logger.AsyncOptions{})
+		l.OnFatal(func() { calls = append(calls, "close db") })
+		l.OnFatal(func() { calls = append(calls, "close file") })
+		l.SetExitFunc(func(code int) {
+			// the queued record is written before exit
+			assert.Contains(t, got.String(), "[FATAL] test Fatalw code=1\n")
+			calls = append(calls, "exit")
+		})
+		l.Fatalw("test Fatalw", "code", 1)
+		assert.Equal(t, []string{"close db", "close file", "exit"}, calls, "they should be equal")
+	})
 }
 
 func TestLogError(t *testing.T) {
@@ -305,3 +338,105 @@ func TestLogTrace(t *testing.T) {
 		assert.Contains(t, got.String(), "[TRACE] test Trace\n")
 	})
 }
+
+func TestLogWith(t *testing.T) {
+	t.Run("test a new logger", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		child := l.With("service", "api", "attempt", 1)
+		child.Infow("test Infow", "user", "jane doe", "ok", true)
+		child.Infof("test Infof %s", "with msg")
+		l.Warnw("test Warnw", logger.Any("empty", ""), "lonely")
+		child.Debugw("test Debugw")
+		assert.Contains(t, got.String(), `[INFO] test Infow service=api attempt=1 user="jane doe" ok=true`+"\n")
+		assert.Contains(t, got.String(), "[INFO] test Infof with msg service=api attempt=1\n")
+		assert.Contains(t, got.String(), `[WARN] test Warnw empty="" !BADKEY=lonely`+"\n")
+		assert.NotContains(t, got.String(), "Debugw")
+	})
+
+	t.Run("test std logger", func(t *testing.T) {
+		l := logger.Default()
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetLevel(logger.LevelInfo)
+		logger.With("service", "api").Errorw("test Errorw", "code", 500)
+		logger.Infow("test Infow", "code", 200)
+		assert.Contains(t, got.String(), "[ERROR] test Errorw service=api code=500\n")
+		assert.Contains(t, got.String(), "[INFO] test Infow code=200\n")
+	})
+}
+
+// callerLine returns the line of its caller.
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+// infoHelper logs through a helper, it reports the caller of the helper.
+func infoHelper(l *logger.Logger, msg string) {
+	l.AddCallerSkip(1).Infoln(msg)
+}
+
+func TestLogCaller(t *testing.T) {
+	t.Run("test a new logger", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(log.Lshortfile)
+		l.Infof("test Infof %s", "with msg")
+		line := callerLine() - 1
+		l.Infoln("test Infoln")
+		l.Info("test Info")
+		l.Infow("test Infow")
+		infoHelper(l, "test helper")
+		assert.Equal(t, fmt.Sprintf("logger_test.go:%d: [INFO] test Infof with msg\n", line)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test Infoln\n", line+2)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test Info\n", line+3)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test Infow\n", line+4)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test helper\n", line+5),
+			got.String(), "they should be equal")
+	})
+
+	t.Run("test std logger", func(t *testing.T) {
+		l := logger.Default()
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetLevel(logger.LevelInfo)
+		l.SetFlags(log.Lshortfile)
+		defer l.SetFlags(log.LstdFlags)
+		logger.Infof("test Infof %s", "with msg")
+		line := callerLine() - 1
+		logger.Infoln("test Infoln")
+		logger.Info("test Info")
+		logger.Infow("test Infow")
+		assert.Equal(t, fmt.Sprintf("logger_test.go:%d: [INFO] test Infof with msg\n", line)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test Infoln\n", line+2)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test Info\n", line+3)+
+			fmt.Sprintf("logger_test.go:%d: [INFO] test Infow\n", line+4),
+			got.String(), "they should be equal")
+	})
+
+	t.Run("test stack trace", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		l.SetStacktraceLevel(logger.LevelError)
+		l.Warnln("test Warnln")
+		assert.Equal(t, "[WARN] test Warnln\n", got.String(), "they should be equal")
+
+		got.Reset()
+		l.Errorln("test Errorln")
+		lines := strings.Split(got.String(), "\n")
+		assert.Equal(t, "[ERROR] test Errorln", lines[0], "they should be equal")
+		assert.Regexp(t, `^goroutine \d+ \[running\]:$`, lines[1])
+		assert.Equal(t, "github.com/PengShaw/GoUtilsKit/logger_test.TestLogCaller.func3()", lines[2], "they should be equal")
+		assert.Contains(t, lines[3], "logger_test.go:")
+
+		got.Reset()
+		l.DisableStacktrace()
+		l.Errorln("test Errorln")
+		assert.Equal(t, "[ERROR] test Errorln\n", got.String(), "they should be equal")
+	})
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// namedMu guards the children of the levels of named loggers.
+var namedMu sync.Mutex
+
+// child returns the level of the named logger name under a, creating it and
+// its ancestors, which inherit their parent level, if needed. The parts of
+// name separated by dots are the names of the successive children.
+func (a *AtomicLevel) child(name string) *AtomicLevel {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	return a.childLocked(name)
+}
+
+func (a *AtomicLevel) childLocked(name string) *AtomicLevel {
+	for _, part := range strings.Split(name, ".") {
+		c, ok := a.children[part]
+		if !ok {
+			c = &AtomicLevel{parent: a}
+			c.inherit.Store(true)
+			if a.children == nil {
+				a.children = make(map[string]*AtomicLevel)
+			}
+			a.children[part] = c
+		}
+		a = c
+	}
+	return a
+}
+
+// inheritLocked makes the descendants of a inherit their parent level.
+func (a *AtomicLevel) inheritLocked() {
+	for _, c := range a.children {
+		c.inherit.Store(true)
+		c.inheritLocked()
+	}
+}
+
+// Named returns a named child logger. The name of a child of a named logger
+// is joined to the name of its parent by a dot, such as "socket.tcp".
+//
+// The named children of loggers sharing a level form a hierarchy: children
+// with the same name share their level, which is inherited from their parent,
+// unless it is set by [Logger.SetLevel], or [SetLevelSpec] for the children
+// of the standard logger. Apart from the level, the child shares the output
+// and copies the configuration of l.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	c := *l
+	c.name = name
+	if l.name != "" {
+		c.name = l.name + "." + name
+	}
+	c.level = l.level.child(name)
+	return &c
+}
+
+// Named returns a named child of the standard logger.
+func Named(name string) *Logger {
+	return std.Named(name)
+}
+
+// Name returns the name of the logger, it is empty if it is not named.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetLevelSpec sets the levels of the standard logger and its named children
+// from a comma-separated spec, such as "info,socket=debug,socket.tcp=trace".
+// A level without name is the level of the standard logger, and the named
+// children which are not in the spec inherit their parent level again.
+func SetLevelSpec(spec string) error {
+	root := LogLevel(-1)
+	levels := make(map[string]LogLevel)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			name, value = "", name
+		}
+		name = strings.TrimSpace(name)
+		level, err := ParseLevel(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("logger: invalid level spec %q: %w", item, err)
+		}
+		if name == "" {
+			root = level
+		} else {
+			levels[name] = level
+		}
+	}
+
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if root >= 0 {
+		std.SetLevel(root)
+	}
+	std.level.inheritLocked()
+	for name, level := range levels {
+		std.level.childLocked(name).SetLevel(level)
+	}
+	return nil
+}
+
+// SetLevelSpecFromEnv sets the levels from the spec in the environment
+// variable key, such as LOG_LEVEL, see [SetLevelSpec]. Nothing is changed
+// if the variable is empty.
+func SetLevelSpecFromEnv(key string) error {
+	spec := os.Getenv(key)
+	if spec == "" {
+		return nil
+	}
+	return SetLevelSpec(spec)
+}
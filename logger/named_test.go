@@ -0,0 +1,72 @@
+package logger_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestNamed(t *testing.T) {
+	std := logger.Default()
+	var got bytes.Buffer
+	std.SetOutput(&got)
+	std.SetFlags(0)
+	defer std.SetFlags(log.LstdFlags)
+	defer logger.SetLevelSpec("info")
+
+	net := logger.Named("net")
+	tcp := net.Named("tcp")
+	assert.Equal(t, "net.tcp", tcp.Name(), "they should be equal")
+
+	t.Run("inherit", func(t *testing.T) {
+		logger.SetLevel(logger.LevelWarn)
+		assert.Equal(t, "Warn", tcp.Level(), "they should be equal")
+		net.SetLevel(logger.LevelDebug)
+		assert.Equal(t, "Debug", tcp.Level(), "they should be equal")
+		assert.Equal(t, "Debug", logger.Named("net").Named("tcp").Level(), "they should be equal")
+		assert.Equal(t, "Warn", logger.Named("other").Level(), "they should be equal")
+	})
+
+	t.Run("spec", func(t *testing.T) {
+		got.Reset()
+		assert.NoError(t, logger.SetLevelSpec("error, net.tcp=trace, net.udp=info"), "should not be an error")
+		assert.Equal(t, "Error", logger.Level(), "they should be equal")
+		assert.Equal(t, "Error", net.Level(), "they should be equal")
+		assert.Equal(t, "Trace", tcp.Level(), "they should be equal")
+		assert.Equal(t, "Info", logger.Named("net.udp").Level(), "they should be equal")
+
+		net.Infoln("test net")
+		tcp.Traceln("test tcp")
+		assert.Equal(t, "[TRACE] test tcp\n", got.String(), "they should be equal")
+
+		assert.Error(t, logger.SetLevelSpec("info,net=loud"), "should be an error")
+		assert.Equal(t, "Trace", tcp.Level(), "should not apply an invalid spec")
+	})
+
+	t.Run("other root", func(t *testing.T) {
+		l := logger.New(logger.LevelTrace)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		db := l.Named("db")
+		assert.Equal(t, "Trace", db.Level(), "should inherit the level of its parent")
+		db.Debugln("test db")
+		assert.Equal(t, "[DEBUG] test db\n", got.String(), "they should be equal")
+
+		db.SetLevel(logger.LevelError)
+		assert.Equal(t, "Trace", logger.New(logger.LevelTrace).Named("db").Level(), "should not share the level of another root")
+		assert.Equal(t, logger.Level(), logger.Named("db").Level(), "should not share the level of another root")
+		assert.NotPanics(t, func() { logger.NewNop().Named("x").Panicln("test nop") }, "should not panic")
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "debug,net=warn")
+		assert.NoError(t, logger.SetLevelSpecFromEnv("LOG_LEVEL"), "should not be an error")
+		assert.Equal(t, "Debug", logger.Level(), "they should be equal")
+		assert.Equal(t, "Warn", tcp.Level(), "they should be equal")
+	})
+}
@@ -0,0 +1,197 @@
+// Package observer provides a [logger.Sink] which keeps records in memory,
+// so tests can assert what code logs without parsing its output.
+//
+//	l, logs := observer.New(logger.LevelInfo)
+//	run(l)
+//	logs.FilterMessage("connected").AssertLen(t, 1)
+package observer
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+// An Entry is a record kept by [Logs], with its caller.
+type Entry struct {
+	logger.Record
+	// Caller is the caller of the record as "dir/file.go:line", it is empty if unknown.
+	Caller string
+}
+
+// Field returns the value of the last field of the entry with key.
+func (e Entry) Field(key string) (any, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Logs is a [logger.Sink] which keeps the records at or above its level.
+// The filters return a snapshot of the entries, which does not observe new records.
+type Logs struct {
+	level logger.LogLevel
+	t     testing.TB
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New creates a logger at level, and the *[Logs] observing its records.
+func New(level logger.LogLevel) (*logger.Logger, *Logs) {
+	logs := &Logs{level: level}
+	l := logger.New(level)
+	l.SetSinks(logs)
+	return l, logs
+}
+
+// NewTest is like [New], it also writes the records to the test log of t,
+// so they are shown when the test fails or runs with -v. The records logged
+// after the test completes, such as by a goroutine it leaks, are still kept,
+// but not written to the test log.
+func NewTest(t testing.TB, level logger.LogLevel) (*logger.Logger, *Logs) {
+	l, logs := New(level)
+	logs.t = t
+	t.Cleanup(func() {
+		logs.mu.Lock()
+		logs.t = nil
+		logs.mu.Unlock()
+	})
+	return l, logs
+}
+
+// Enabled implements [logger.Sink].
+func (o *Logs) Enabled(level logger.LogLevel) bool {
+	return level >= o.level
+}
+
+// Write implements [logger.Sink].
+func (o *Logs) Write(r *logger.Record) error {
+	e := Entry{Record: *r}
+	e.Fields = slices.Clone(r.Fields)
+	if r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.Caller = shortCaller(f)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, e)
+	// t is logged under the lock, so the test cannot complete meanwhile
+	if o.t != nil {
+		var buf bytes.Buffer
+		logger.TextEncoder{}.Encode(&buf, r)
+		o.t.Log(e.Caller + ": " + strings.TrimSuffix(buf.String(), "\n"))
+	}
+	return nil
+}
+
+// All returns the entries in the order they are logged.
+func (o *Logs) All() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return slices.Clone(o.entries)
+}
+
+// TakeAll returns the entries, and removes them.
+func (o *Logs) TakeAll() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// Len returns the number of entries.
+func (o *Logs) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Messages returns the messages of the entries.
+func (o *Logs) Messages() []string {
+	entries := o.All()
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+// Filter returns the entries for which keep returns true.
+func (o *Logs) Filter(keep func(e Entry) bool) *Logs {
+	filtered := &Logs{level: o.level}
+	for _, e := range o.All() {
+		if keep(e) {
+			filtered.entries = append(filtered.entries, e)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel returns the entries at level.
+func (o *Logs) FilterLevel(level logger.LogLevel) *Logs {
+	return o.Filter(func(e Entry) bool { return e.Level == level })
+}
+
+// FilterMinLevel returns the entries at or above level.
+func (o *Logs) FilterMinLevel(level logger.LogLevel) *Logs {
+	return o.Filter(func(e Entry) bool { return e.Level >= level })
+}
+
+// FilterMessage returns the entries with the message msg.
+func (o *Logs) FilterMessage(msg string) *Logs {
+	return o.Filter(func(e Entry) bool { return e.Message == msg })
+}
+
+// FilterMessageSnippet returns the entries whose message contains snippet.
+func (o *Logs) FilterMessageSnippet(snippet string) *Logs {
+	return o.Filter(func(e Entry) bool { return strings.Contains(e.Message, snippet) })
+}
+
+// FilterField returns the entries with a field of key and value, the values
+// are compared by reflect.DeepEqual.
+func (o *Logs) FilterField(key string, value any) *Logs {
+	return o.Filter(func(e Entry) bool {
+		v, ok := e.Field(key)
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// AssertLen reports an error to t, and returns false, if the number of
+// entries is not n.
+func (o *Logs) AssertLen(t testing.TB, n int) bool {
+	t.Helper()
+	entries := o.All()
+	if len(entries) == n {
+		return true
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString("\n\t")
+		b.WriteString(e.Level.String() + ": " + e.Message)
+	}
+	t.Errorf("observer: got %d entries, want %d%s", len(entries), n, b.String())
+	return false
+}
+
+// shortCaller formats f as "dir/file.go:line".
+func shortCaller(f runtime.Frame) string {
+	file := f.File
+	if i := strings.LastIndexByte(file, '/'); i > 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(f.Line)
+}
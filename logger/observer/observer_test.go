@@ -0,0 +1,83 @@
+package observer_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+	"github.com/PengShaw/GoUtilsKit/logger/observer"
+)
+
+// fakeTB records the error reported by an assertion, and what is logged.
+type fakeTB struct {
+	testing.TB
+	msg      string
+	logs     int
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Log(args ...any) { tb.logs++ }
+
+func (tb *fakeTB) Cleanup(f func()) { tb.cleanups = append(tb.cleanups, f) }
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.msg = fmt.Sprintf(format, args...)
+}
+
+func TestObserver(t *testing.T) {
+	l, logs := observer.New(logger.LevelInfo)
+	l.Debugln("test Debugln")
+	l.Infow("test Infow", "code", 200)
+	l.With("service", "api").Warnf("test Warnf %d", 1)
+	l.Errorw("test Errorw", "code", 500, "tags", []string{"a"})
+
+	assert.Equal(t, 3, logs.Len(), "they should be equal")
+	assert.Equal(t, []string{"test Infow", "test Warnf 1", "test Errorw"}, logs.Messages(), "they should be equal")
+
+	e := logs.FilterLevel(logger.LevelWarn).All()[0]
+	assert.Equal(t, "test Warnf 1", e.Message, "they should be equal")
+	assert.Equal(t, []logger.Field{logger.Any("service", "api")}, e.Fields, "they should be equal")
+	assert.Contains(t, e.Caller, "observer/observer_test.go:")
+
+	logs.FilterMinLevel(logger.LevelWarn).AssertLen(t, 2)
+	logs.FilterMessageSnippet("Infow").AssertLen(t, 1)
+	logs.FilterField("code", 500).AssertLen(t, 1)
+	logs.FilterField("tags", []string{"a"}).AssertLen(t, 1)
+	logs.FilterMessage("missing").AssertLen(t, 0)
+
+	assert.Len(t, logs.TakeAll(), 3)
+	assert.Equal(t, 0, logs.Len(), "they should be equal")
+
+	t.Run("assert len", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		l, logs := observer.New(logger.LevelInfo)
+		l.Infoln("test Infoln")
+		assert.False(t, logs.AssertLen(tb, 2), "should fail")
+		assert.Equal(t, "observer: got 1 entries, want 2\n\tInfo: test Infoln", tb.msg, "they should be equal")
+	})
+
+	t.Run("test logger", func(t *testing.T) {
+		l, logs := observer.NewTest(t, logger.LevelDebug)
+		l.Debugw("test Debugw", "user", "jane")
+		v, ok := logs.All()[0].Field("user")
+		assert.True(t, ok, "should have the field")
+		assert.Equal(t, "jane", v, "they should be equal")
+	})
+
+	t.Run("test logger after the test", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		l, logs := observer.NewTest(tb, logger.LevelInfo)
+		l.Infoln("test Infoln")
+		// the test completes
+		for _, f := range tb.cleanups {
+			f()
+		}
+		l.Infoln("test Infoln again")
+		assert.Len(t, logs.All(), 2)
+		assert.Equal(t, 1, tb.logs, "should not log to the completed test")
+	})
+}
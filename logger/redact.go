@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultMask replaces the redacted data by default.
+const DefaultMask = "***"
+
+// A RedactRule redacts the matches of a pattern in the messages and field
+// values of records.
+type RedactRule struct {
+	// Name identifies the rule in the allowlist of a logger.
+	Name    string
+	Pattern *regexp.Regexp
+	// Replacement replaces each match, with the $1 expansion of
+	// Regexp.ReplaceAllString. The mask of the [Redactor] is used if it is empty.
+	Replacement string
+	// Check, if it is set, reports whether a match is redacted, such as the
+	// checksum of a credit card number.
+	Check func(match string) bool
+}
+
+// The built-in redaction rules.
+var (
+	RedactEmail = RedactRule{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	RedactBearerToken = RedactRule{
+		Name:        "bearer",
+		Pattern:     regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		Replacement: "${1}" + DefaultMask,
+	}
+	RedactCreditCard = RedactRule{
+		Name:    "credit_card",
+		Pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		Check:   luhn,
+	}
+	RedactIP = RedactRule{
+		Name:    "ip",
+		Pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|[0-9A-Fa-f]*:[0-9A-Fa-f:]*:[0-9A-Fa-f.]*`),
+		Check:   func(s string) bool { return net.ParseIP(s) != nil },
+	}
+)
+
+// DefaultRedactRules returns the built-in redaction rules.
+func DefaultRedactRules() []RedactRule {
+	return []RedactRule{RedactEmail, RedactBearerToken, RedactCreditCard, RedactIP}
+}
+
+// RedactOptions configures a [Redactor].
+type RedactOptions struct {
+	// Rules redact the matches in messages and field values.
+	Rules []RedactRule
+	// Fields are the keys of the fields whose values are masked,
+	// such as "password" and "token". Keys are case-insensitive.
+	Fields []string
+	// Mask replaces the redacted data, DefaultMask if it is empty.
+	Mask string
+}
+
+// A Redactor removes sensitive data, such as tokens and personal data, from
+// records before they are encoded. It redacts the message and the values of
+// the fields which are strings, errors, fmt.Stringers or []byte.
+type Redactor struct {
+	rules  []RedactRule
+	fields map[string]bool
+	mask   string
+}
+
+// NewRedactor creates a *[Redactor].
+func NewRedactor(opts RedactOptions) *Redactor {
+	r := &Redactor{
+		rules:  opts.Rules,
+		fields: make(map[string]bool, len(opts.Fields)),
+		mask:   opts.Mask,
+	}
+	if r.mask == "" {
+		r.mask = DefaultMask
+	}
+	for _, key := range opts.Fields {
+		r.fields[strings.ToLower(key)] = true
+	}
+	return r
+}
+
+// Redact redacts rec, except the rules and field keys in allow. The fields
+// of rec are copied before they are changed, as they may be shared.
+func (r *Redactor) Redact(rec *Record, allow []string) {
+	rec.Message = r.redactString(rec.Message, allow)
+	cloned := false
+	set := func(i int, v any) {
+		if !cloned {
+			rec.Fields = slices.Clone(rec.Fields)
+			cloned = true
+		}
+		rec.Fields[i].Value = v
+	}
+	for i, f := range rec.Fields {
+		key := strings.ToLower(f.Key)
+		if r.fields[key] && !slices.Contains(allow, key) {
+			set(i, r.mask)
+			continue
+		}
+		var s string
+		switch v := f.Value.(type) {
+		case errorValue:
+			if info, ok := r.redactError(v.inspect(), allow); ok {
+				set(i, errorValue{err: v.err, info: &info})
+			}
+			continue
+		case string:
+			s = v
+		case error:
+			s = v.Error()
+		case fmt.Stringer:
+			s = v.String()
+		case []byte:
+			s = string(v)
+		default:
+			continue
+		}
+		if redacted := r.redactString(s, allow); redacted != s {
+			set(i, redacted)
+		}
+	}
+}
+
+// redactError redacts the messages and fields of the chain of an error
+// field, it reports whether anything is redacted.
+func (r *Redactor) redactError(info errorInfo, allow []string) (errorInfo, bool) {
+	msg := r.redactString(info.msg, allow)
+	changed := msg != info.msg
+	info.msg = msg
+	if len(info.fields) > 0 {
+		rec := Record{Fields: info.fields}
+		r.Redact(&rec, allow)
+		changed = changed || &rec.Fields[0] != &info.fields[0]
+		info.fields = rec.Fields
+	}
+	if len(info.causes) > 0 {
+		causes := make([]errorInfo, len(info.causes))
+		for i, c := range info.causes {
+			var ok bool
+			causes[i], ok = r.redactError(c, allow)
+			changed = changed || ok
+		}
+		info.causes = causes
+	}
+	return info, changed
+}
+
+func (r *Redactor) redactString(s string, allow []string) string {
+	for _, rule := range r.rules {
+		if slices.Contains(allow, strings.ToLower(rule.Name)) {
+			continue
+		}
+		repl := rule.Replacement
+		if repl == "" {
+			repl = r.mask
+		}
+		if rule.Check == nil {
+			s = rule.Pattern.ReplaceAllString(s, repl)
+			continue
+		}
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(m string) string {
+			if !rule.Check(m) {
+				return m
+			}
+			return rule.Pattern.ReplaceAllString(m, repl)
+		})
+	}
+	return s
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// SetRedactor sets the redactor of the logger, it applies to the records
+// before the hooks, so they never see the redacted data, and again to the
+// fields added by the hooks, before any encoder or sink. The children created by
+// [Logger.With] after the call share the redactor.
+//
+// SetRedactor must not be called concurrently with output.
+func (l *Logger) SetRedactor(r *Redactor) {
+	l.redactor = r
+}
+
+// SetRedactor sets the redactor of the standard logger.
+func SetRedactor(r *Redactor) {
+	std.SetRedactor(r)
+}
+
+// AllowRedacted returns a child logger which does not redact the rules and
+// field keys named by names, such as an audit logger which keeps IP addresses.
+func (l *Logger) AllowRedacted(names ...string) *Logger {
+	c := *l
+	c.redactAllow = slices.Clone(l.redactAllow)
+	for _, name := range names {
+		c.redactAllow = append(c.redactAllow, strings.ToLower(name))
+	}
+	return &c
+}
@@ -0,0 +1,91 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestRedactor(t *testing.T) {
+	r := logger.NewRedactor(logger.RedactOptions{
+		Rules:  logger.DefaultRedactRules(),
+		Fields: []string{"password"},
+	})
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"mail jane.doe@example.com now", "mail *** now"},
+		{"Authorization: Bearer eyJhbGciOi.J9-x_y==", "Authorization: Bearer ***"},
+		{"card 4111 1111 1111 1111 paid", "card *** paid"},
+		{"order 1234567890123 paid", "order 1234567890123 paid"},
+		{"received data from 192.168.1.10:5000", "received data from ***:5000"},
+		{"received data from [::1]:5000", "received data from [***]:5000"},
+		{"start at 12:30:45", "start at 12:30:45"},
+	}
+	for _, tt := range tests {
+		rec := &logger.Record{Message: tt.msg}
+		r.Redact(rec, nil)
+		assert.Equal(t, tt.want, rec.Message, "they should be equal")
+	}
+
+	t.Run("fields", func(t *testing.T) {
+		fields := []logger.Field{
+			logger.Any("Password", "hunter2"),
+			logger.Any("err", errors.New("dial 10.0.0.1 failed")),
+			logger.Any("data", []byte("to jane@example.com")),
+			logger.Any("n", 1),
+		}
+		rec := &logger.Record{Fields: fields}
+		r.Redact(rec, nil)
+		assert.Equal(t, []logger.Field{
+			logger.Any("Password", "***"),
+			logger.Any("err", "dial *** failed"),
+			logger.Any("data", "to ***"),
+			logger.Any("n", 1),
+		}, rec.Fields, "they should be equal")
+		assert.Equal(t, "hunter2", fields[0].Value, "should not modify the fields in place")
+	})
+}
+
+func TestLogRedact(t *testing.T) {
+	l := logger.New(logger.LevelDebug)
+	var got bytes.Buffer
+	l.SetOutput(&got)
+	l.SetFlags(0)
+	l.SetRedactor(logger.NewRedactor(logger.RedactOptions{
+		Rules: []logger.RedactRule{
+			logger.RedactIP,
+			{Name: "session", Pattern: regexp.MustCompile(`sid=\w+`), Replacement: "sid=<hidden>"},
+		},
+		Fields: []string{"token"},
+		Mask:   "[REDACTED]",
+	}))
+
+	child := l.With("token", "abc")
+	child.Debugf("received data from %s: %s", "10.0.0.1:80", "sid=42")
+	child.AllowRedacted("ip", "token").Infoln("test audit 10.0.0.1 sid=42")
+	child.Infoln("test again")
+	assert.Equal(t, "[DEBUG] received data from [REDACTED]:80: sid=<hidden> token=[REDACTED]\n"+
+		"[INFO] test audit 10.0.0.1 sid=<hidden> token=abc\n"+
+		"[INFO] test again token=[REDACTED]\n", got.String(), "they should be equal")
+
+	t.Run("hooks", func(t *testing.T) {
+		got.Reset()
+		var alerts []string
+		l := l.With()
+		l.AddHook(logger.LevelError, logger.HookFunc(func(r *logger.Record) bool {
+			alerts = append(alerts, r.Message)
+			r.Fields = append(r.Fields, logger.Any("peer", "10.0.0.2"))
+			return true
+		}))
+		l.Errorf("dial %s failed", "10.0.0.1")
+		assert.Equal(t, []string{"dial [REDACTED] failed"}, alerts, "hooks should get the redacted record")
+		assert.Equal(t, "[ERROR] dial [REDACTED] failed peer=[REDACTED]\n", got.String(), "they should be equal")
+	})
+}
@@ -0,0 +1,375 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A RotateInterval is the period of time based rotation of a [RotatingFile].
+type RotateInterval int
+
+const (
+	// RotateNever disables time based rotation.
+	RotateNever RotateInterval = iota
+	// RotateHourly rotates at the beginning of each hour.
+	RotateHourly
+	// RotateDaily rotates at midnight.
+	RotateDaily
+)
+
+// backupTimeFormat is the time format in the names of backup files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions configures a [RotatingFile]. The zero value never rotates.
+type RotateOptions struct {
+	// MaxSize is the size in bytes at which the file is rotated, 0 means no limit.
+	MaxSize int64
+	// Interval is the period of time based rotation, it can be used with MaxSize.
+	Interval RotateInterval
+	// MaxBackups is the number of backup files to keep, 0 keeps all of them.
+	MaxBackups int
+	// MaxAge is the age after which backup files are removed, 0 keeps all of them.
+	MaxAge time.Duration
+	// Compress gzips the backup files.
+	Compress bool
+	// UTC uses UTC instead of local time for the interval and backup names.
+	UTC bool
+}
+
+// A RotatingFile is an io.WriteCloser which writes to a file, and rotates it
+// by size and time. It can be used as the output of a [Logger]:
+//
+//	f, err := logger.NewRotatingFile("/var/log/app.log", logger.RotateOptions{MaxSize: 100 << 20})
+//	logger.Default().SetOutput(f)
+//
+// A rotated file is renamed to a backup, such as app-2009-01-23T01-23-23.000.log,
+// and a new file is created.
+type RotatingFile struct {
+	filename string
+	opts     RotateOptions
+
+	mu sync.Mutex
+	// file is nil after a failed rotation or reopen, until it is opened again.
+	file   *os.File
+	closed bool
+	size   int64
+	next   time.Time
+
+	// millMu serializes the compression and removal of backups.
+	millMu sync.Mutex
+	millWg sync.WaitGroup
+}
+
+// NewRotatingFile opens filename for appending, creating it and its
+// directory if needed, and returns a *[RotatingFile] writing to it.
+func NewRotatingFile(filename string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{filename: filename, opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write implements io.Writer, it rotates the file before writing p if the
+// file would exceed MaxSize, or the interval is over. If the rotation fails,
+// p is still written to the file when possible, and the error is returned.
+// If the file could not be opened again, the next Write retries.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	var rotateErr error
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	} else if (f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxSize) ||
+		(!f.next.IsZero() && !f.now().Before(f.next)) {
+		rotateErr = f.rotate()
+		if f.file == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Sync commits the content of the file to stable storage.
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Rotate rotates the file now.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file == nil {
+		return f.open()
+	}
+	return f.rotate()
+}
+
+// Reopen reopens the file, so a file moved by an external tool, such as
+// logrotate, is replaced by a new one. The current file is kept if it fails.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	old := f.file
+	if err := f.open(); err != nil {
+		return err
+	}
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// ReopenOnSignal reopens the file each time one of sigs is received,
+// SIGHUP if sigs is empty. On platforms without SIGHUP, nothing is
+// received if sigs is empty. Call stop to stop listening to the signals.
+func (f *RotatingFile) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = reopenSignals
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	// Notify relays all signals if none is given
+	if len(sigs) > 0 {
+		signal.Notify(ch, sigs...)
+	}
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := f.Reopen(); err != nil && !errors.Is(err, os.ErrClosed) {
+					Errorf("reopen %s failed: %s", f.filename, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file, and waits for the backups to be compressed and removed.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.closed = true
+	f.mu.Unlock()
+	f.millWg.Wait()
+	return err
+}
+
+func (f *RotatingFile) now() time.Time {
+	if f.opts.UTC {
+		return time.Now().UTC()
+	}
+	return time.Now()
+}
+
+// open opens the file, and sets it as the file written to if it succeeds.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.filename), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.next = f.nextRotation(f.now())
+	return nil
+}
+
+// nextRotation returns the time of the next time based rotation after t.
+func (f *RotatingFile) nextRotation(t time.Time) time.Time {
+	switch f.opts.Interval {
+	case RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Time{}
+}
+
+// rotate renames the file to a backup, and opens a new one. If the rename
+// fails, the file is opened again to keep writing to it. If the open fails,
+// the file is nil.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	// the file is unusable even if Close fails, the next Write reopens it
+	f.file = nil
+	if err != nil {
+		return err
+	}
+	prefix, ext := f.backupName()
+	t := f.now()
+	backup := prefix + t.Format(backupTimeFormat) + ext
+	for fileExists(backup) || fileExists(backup+".gz") {
+		// do not overwrite a backup rotated in the same millisecond
+		t = t.Add(time.Millisecond)
+		backup = prefix + t.Format(backupTimeFormat) + ext
+	}
+	if err := os.Rename(f.filename, backup); err != nil && !os.IsNotExist(err) {
+		f.open()
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.millWg.Add(1)
+	go func() {
+		defer f.millWg.Done()
+		f.mill(backup)
+	}()
+	return nil
+}
+
+// backupName returns the parts of the backup names around the timestamp.
+func (f *RotatingFile) backupName() (prefix, ext string) {
+	ext = filepath.Ext(f.filename)
+	return strings.TrimSuffix(f.filename, ext) + "-", ext
+}
+
+// mill compresses the new backup, and removes the backups beyond MaxBackups or MaxAge.
+func (f *RotatingFile) mill(backup string) {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	if f.opts.Compress {
+		// the backup may be removed already by the mill of a later rotation
+		if err := compressFile(backup); err != nil && !os.IsNotExist(err) {
+			Errorf("compress %s failed: %s", backup, err)
+		}
+	}
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+
+	type backupFile struct {
+		path string
+		t    time.Time
+	}
+	prefix, ext := f.backupName()
+	entries, err := os.ReadDir(filepath.Dir(f.filename))
+	if err != nil {
+		Errorf("list backups of %s failed: %s", f.filename, err)
+		return
+	}
+	var backups []backupFile
+	for _, e := range entries {
+		path := filepath.Join(filepath.Dir(f.filename), e.Name())
+		ts, ok := strings.CutPrefix(path, prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		ts, ok = strings.CutSuffix(strings.TrimSuffix(ts, ".gz"), ext)
+		if !ok {
+			continue
+		}
+		loc := time.Local
+		if f.opts.UTC {
+			loc = time.UTC
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, ts, loc)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: path, t: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].t.After(backups[j].t) })
+
+	for i, b := range backups {
+		if (f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups) ||
+			(f.opts.MaxAge > 0 && time.Since(b.t) > f.opts.MaxAge) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				Errorf("remove backup %s failed: %s", b.path, err)
+			}
+		}
+	}
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// compressFile gzips name to name.gz, and removes name.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,11 @@
+//go:build js || plan9
+
+package logger
+
+import (
+	"os"
+)
+
+// reopenSignals are the default signals of [RotatingFile.ReopenOnSignal],
+// there is no SIGHUP on these platforms.
+var reopenSignals []os.Signal
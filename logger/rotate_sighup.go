@@ -0,0 +1,11 @@
+//go:build !js && !plan9
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// reopenSignals are the default signals of [RotatingFile.ReopenOnSignal].
+var reopenSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,99 @@
+package logger_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("test rotate by size", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, "app.log")
+		f, err := logger.NewRotatingFile(name, logger.RotateOptions{MaxSize: 64, MaxBackups: 2, Compress: true})
+		assert.NoError(t, err, "should not be an error")
+
+		l := logger.New(logger.LevelInfo)
+		l.SetOutput(f)
+		l.SetFlags(0)
+		for i := 0; i < 10; i++ {
+			// each record is 35 bytes, so each file holds one record
+			l.Infof("test Infof %s %d", strings.Repeat("x", 15), i)
+		}
+		assert.NoError(t, f.Close(), "should not be an error")
+
+		b, err := os.ReadFile(name)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, "[INFO] test Infof xxxxxxxxxxxxxxx 9\n", string(b), "they should be equal")
+
+		backups, err := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Len(t, backups, 2)
+		zr, err := gzip.NewReader(openFile(t, backups[1]))
+		assert.NoError(t, err, "should not be an error")
+		b, err = io.ReadAll(zr)
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, "[INFO] test Infof xxxxxxxxxxxxxxx 8\n", string(b), "they should be equal")
+	})
+
+	t.Run("test reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, "app.log")
+		f, err := logger.NewRotatingFile(name, logger.RotateOptions{})
+		assert.NoError(t, err, "should not be an error")
+		defer f.Close()
+
+		_, err = f.Write([]byte("first\n"))
+		assert.NoError(t, err, "should not be an error")
+		// as logrotate does
+		assert.NoError(t, os.Rename(name, name+".1"), "should not be an error")
+		assert.NoError(t, f.Reopen(), "should not be an error")
+		_, err = f.Write([]byte("second\n"))
+		assert.NoError(t, err, "should not be an error")
+
+		b, _ := os.ReadFile(name + ".1")
+		assert.Equal(t, "first\n", string(b), "they should be equal")
+		b, _ = os.ReadFile(name)
+		assert.Equal(t, "second\n", string(b), "they should be equal")
+	})
+
+	t.Run("test reopen failure", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, "app.log")
+		f, err := logger.NewRotatingFile(name, logger.RotateOptions{})
+		assert.NoError(t, err, "should not be an error")
+		defer f.Close()
+
+		// the file cannot be opened while a directory is in the way
+		assert.NoError(t, os.Rename(name, name+".1"), "should not be an error")
+		assert.NoError(t, os.Mkdir(name, 0o755), "should not be an error")
+		assert.Error(t, f.Reopen(), "should be an error")
+		_, err = f.Write([]byte("first\n"))
+		assert.NoError(t, err, "should keep writing to the current file")
+
+		assert.NoError(t, os.Remove(name), "should not be an error")
+		assert.NoError(t, f.Reopen(), "should not be an error")
+		_, err = f.Write([]byte("second\n"))
+		assert.NoError(t, err, "should not be an error")
+
+		b, _ := os.ReadFile(name + ".1")
+		assert.Equal(t, "first\n", string(b), "they should be equal")
+		b, _ = os.ReadFile(name)
+		assert.Equal(t, "second\n", string(b), "they should be equal")
+	})
+}
+
+func openFile(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(name)
+	assert.NoError(t, err, "should not be an error")
+	t.Cleanup(func() { f.Close() })
+	return f
+}
@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SamplerOptions configures the sampling of a [Logger]. Records at
+// LevelFatal and LevelPanic are never sampled.
+type SamplerOptions struct {
+	// Interval is the period of the counts of messages, 1 second if it is 0.
+	Interval time.Duration
+	// First is the number of records of each message logged in an interval,
+	// after which only every Thereafter-th record is logged. A Thereafter of
+	// 0 drops the rest of the interval. Messages are not sampled if both are 0.
+	//
+	// A message is identified by its level and template, which is the format
+	// of the printf-like methods, and the message of the others.
+	First      int
+	Thereafter int
+	// Limits are the token bucket limits of the records per level.
+	Limits map[LogLevel]RateLimit
+	// SummaryInterval is the period of the Warn records summarizing the
+	// number of suppressed records per message, 0 disables the summary.
+	// The summary is written with the first record after the period, and
+	// by [Logger.Close]. Past 1024 messages in a period, the records of new
+	// messages are summarized per level as "(other messages)".
+	SummaryInterval time.Duration
+}
+
+// A RateLimit is a token bucket, Rate records per second are allowed,
+// with bursts up to Burst records.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// maxSuppressedMessages bounds the messages counted for the summary, the
+// records of the other messages are counted by level as otherMessages.
+const (
+	maxSuppressedMessages = 1024
+	otherMessages         = "(other messages)"
+)
+
+// sampleKey identifies the message of a record.
+type sampleKey struct {
+	level LogLevel
+	tmpl  string
+}
+
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket if there is one.
+func (b *bucket) allow(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	}
+	b.last = now
+	if b.tokens > float64(b.limit.Burst) {
+		b.tokens = float64(b.limit.Burst)
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type sampler struct {
+	opts SamplerOptions
+
+	mu          sync.Mutex
+	reset       time.Time
+	counts      map[sampleKey]int
+	buckets     map[LogLevel]*bucket
+	suppressed  map[sampleKey]uint64
+	nextSummary time.Time
+}
+
+func newSampler(opts SamplerOptions) *sampler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	s := &sampler{
+		opts:       opts,
+		counts:     make(map[sampleKey]int),
+		buckets:    make(map[LogLevel]*bucket),
+		suppressed: make(map[sampleKey]uint64),
+	}
+	for level, limit := range opts.Limits {
+		s.buckets[level] = &bucket{limit: limit, tokens: float64(limit.Burst)}
+	}
+	return s
+}
+
+// allow reports whether a record of key is logged at now, and returns the
+// suppressed counts if the summary is due.
+func (s *sampler) allow(key sampleKey, now time.Time) (bool, map[sampleKey]uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.reset) >= s.opts.Interval {
+		clear(s.counts)
+		s.reset = now
+	}
+	ok := true
+	if s.opts.First > 0 || s.opts.Thereafter > 0 {
+		s.counts[key]++
+		n := s.counts[key]
+		ok = n <= s.opts.First || (s.opts.Thereafter > 0 && (n-s.opts.First)%s.opts.Thereafter == 0)
+	}
+	if b := s.buckets[key.level]; ok && b != nil {
+		ok = b.allow(now)
+	}
+	var summary map[sampleKey]uint64
+	if s.opts.SummaryInterval > 0 {
+		if !ok {
+			if _, found := s.suppressed[key]; !found && len(s.suppressed) >= maxSuppressedMessages {
+				key.tmpl = otherMessages
+			}
+			s.suppressed[key]++
+		}
+		if s.nextSummary.IsZero() {
+			s.nextSummary = now.Add(s.opts.SummaryInterval)
+		} else if !now.Before(s.nextSummary) {
+			summary = s.takeSummary()
+			s.nextSummary = now.Add(s.opts.SummaryInterval)
+		}
+	}
+	return ok, summary
+}
+
+// takeSummary returns the suppressed counts, and resets them.
+func (s *sampler) takeSummary() map[sampleKey]uint64 {
+	if len(s.suppressed) == 0 {
+		return nil
+	}
+	summary := s.suppressed
+	s.suppressed = make(map[sampleKey]uint64)
+	return summary
+}
+
+// SetSampler samples the records of the logger, and the children created
+// by [Logger.With] after the call. SetSampler must not be called
+// concurrently with output.
+func (l *Logger) SetSampler(opts SamplerOptions) {
+	l.sampler = newSampler(opts)
+}
+
+// SetSampler samples the records of the standard logger.
+func SetSampler(opts SamplerOptions) {
+	std.SetSampler(opts)
+}
+
+// sample reports whether a record of the template at level is logged, and
+// writes the summary of the suppressed records if it is due.
+func (l *Logger) sample(level LogLevel, tmpl string) bool {
+	if l.sampler == nil || level >= LevelFatal {
+		return true
+	}
+	ok, summary := l.sampler.allow(sampleKey{level: level, tmpl: tmpl}, time.Now())
+	l.writeSummary(summary)
+	return ok
+}
+
+// flushSummary writes the summary of the suppressed records now.
+func (l *Logger) flushSummary() {
+	if l.sampler == nil || l.sampler.opts.SummaryInterval <= 0 {
+		return
+	}
+	l.sampler.mu.Lock()
+	summary := l.sampler.takeSummary()
+	l.sampler.mu.Unlock()
+	l.writeSummary(summary)
+}
+
+// writeSummary writes a Warn record per message with suppressed records.
+func (l *Logger) writeSummary(summary map[sampleKey]uint64) {
+	if len(summary) == 0 {
+		return
+	}
+	keys := make([]sampleKey, 0, len(summary))
+	for k := range summary {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].tmpl < keys[j].tmpl
+	})
+	for _, k := range keys {
+		l.write(&Record{
+			Time:    time.Now(),
+			Level:   LevelWarn,
+			Message: "logger suppressed records",
+			Fields: []Field{
+				{Key: "sampled_level", Value: k.level.String()},
+				{Key: "sampled_message", Value: k.tmpl},
+				{Key: "suppressed", Value: summary[k]},
+			},
+		})
+	}
+}
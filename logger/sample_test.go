@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestSampler(t *testing.T) {
+	t.Run("first and thereafter", func(t *testing.T) {
+		l := logger.New(logger.LevelInfo)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		l.SetSampler(logger.SamplerOptions{Interval: time.Hour, First: 2, Thereafter: 3})
+		for i := 1; i <= 10; i++ {
+			l.Infof("received data from %d", i)
+			l.Warnln("test Warnln")
+		}
+		l.Errorw("test Errorw")
+		assert.Equal(t, []string{
+			"[INFO] received data from 1",
+			"[WARN] test Warnln",
+			"[INFO] received data from 2",
+			"[WARN] test Warnln",
+			"[INFO] received data from 5",
+			"[WARN] test Warnln",
+			"[INFO] received data from 8",
+			"[WARN] test Warnln",
+			"[ERROR] test Errorw",
+		}, strings.Split(strings.TrimSuffix(got.String(), "\n"), "\n"), "they should be equal")
+	})
+
+	t.Run("rate limit and summary", func(t *testing.T) {
+		l := logger.New(logger.LevelTrace)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		l.SetSampler(logger.SamplerOptions{
+			Limits:          map[logger.LogLevel]logger.RateLimit{logger.LevelDebug: {Rate: 0, Burst: 2}},
+			SummaryInterval: time.Hour,
+		})
+		for i := 0; i < 5; i++ {
+			l.Debugf("test Debugf %d", i)
+			l.Tracef("test Tracef %d", i)
+		}
+		assert.Equal(t, 2, strings.Count(got.String(), "[DEBUG] test Debugf"), "they should be equal")
+		assert.Equal(t, 5, strings.Count(got.String(), "[TRACE] test Tracef"), "they should be equal")
+
+		got.Reset()
+		assert.Nil(t, l.Close(), "should not be an error")
+		assert.Equal(t, `[WARN] logger suppressed records sampled_level=Debug sampled_message="test Debugf %d" suppressed=3`+"\n",
+			got.String(), "they should be equal")
+	})
+
+	t.Run("summary of many messages", func(t *testing.T) {
+		l := logger.New(logger.LevelDebug)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		l.SetSampler(logger.SamplerOptions{
+			Limits:          map[logger.LogLevel]logger.RateLimit{logger.LevelDebug: {Rate: 0, Burst: 0}},
+			SummaryInterval: time.Hour,
+		})
+		for i := 0; i < 1030; i++ {
+			l.Debugln("test Debugln", i)
+		}
+		assert.Nil(t, l.Close(), "should not be an error")
+		assert.Equal(t, 1025, strings.Count(got.String(), "[WARN] logger suppressed records"), "they should be equal")
+		assert.Contains(t, got.String(), `sampled_message="(other messages)" suppressed=6`+"\n")
+	})
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"sync"
+)
+
+// A Sink is a destination of the records of a [Logger]. A Logger with sinks
+// writes each record to every sink enabled for its level, instead of its output.
+type Sink interface {
+	// Enabled reports whether the sink writes records at level.
+	Enabled(level LogLevel) bool
+	// Write writes r, it must not retain r after returning.
+	Write(r *Record) error
+}
+
+// A WriterSink is a [Sink] which encodes records at or above its level to
+// an io.Writer.
+type WriterSink struct {
+	w     io.Writer
+	level *AtomicLevel
+	enc   Encoder
+	mu    sync.Mutex
+}
+
+// NewWriterSink creates a *[WriterSink] which writes records at or above
+// level to w, encoded by enc. A nil enc is a [TextEncoder] with log.LstdFlags.
+func NewWriterSink(w io.Writer, level LogLevel, enc Encoder) *WriterSink {
+	if enc == nil {
+		enc = TextEncoder{Flags: log.LstdFlags}
+	}
+	return &WriterSink{w: w, level: NewAtomicLevel(level), enc: enc}
+}
+
+// SetLevel sets the minimum level of the sink. It is safe to call while
+// the sink is used.
+func (s *WriterSink) SetLevel(level LogLevel) {
+	s.level.SetLevel(level)
+}
+
+// Enabled implements [Sink].
+func (s *WriterSink) Enabled(level LogLevel) bool {
+	return s.level.Level() <= level
+}
+
+// Write implements [Sink].
+func (s *WriterSink) Write(r *Record) error {
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	buf.Reset()
+	if err := s.enc.Encode(buf, r); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+// Sync syncs the writer if it has a Sync method, such as *os.File.
+func (s *WriterSink) Sync() error {
+	if w, ok := s.w.(syncer); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return w.Sync()
+	}
+	return nil
+}
+
+// NewSlogSink creates a [Sink] which writes records to h, it is enabled
+// for the levels enabled by h.
+func NewSlogSink(h slog.Handler) Sink {
+	return slogSink{h: h}
+}
+
+type slogSink struct {
+	h slog.Handler
+}
+
+func (s slogSink) Enabled(level LogLevel) bool {
+	return s.h.Enabled(context.Background(), level.SlogLevel())
+}
+
+func (s slogSink) Write(r *Record) error {
+	sr := slog.NewRecord(r.Time, r.Level.SlogLevel(), r.Message, r.PC)
+	for _, f := range r.Fields {
+		sr.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	if r.Stack != "" {
+		sr.AddAttrs(slog.String("stack", r.Stack))
+	}
+	return s.h.Handle(context.Background(), sr)
+}
+
+// SetSinks sets the sinks of the logger, which replace its encoder and
+// output. A record is written to the sinks enabled for its level, if it is
+// at or above the level of the logger. Calling SetSinks without sinks
+// restores the output of the logger.
+//
+// SetSinks must not be called concurrently with output, the children
+// created by [Logger.With] before the call keep the previous sinks.
+func (l *Logger) SetSinks(sinks ...Sink) {
+	l.sinks = sinks
+}
+
+// SetSinks sets the sinks of the standard logger.
+func SetSinks(sinks ...Sink) {
+	std.SetSinks(sinks...)
+}
+
+// writeSinks writes r to the sinks enabled for its level.
+func (l *Logger) writeSinks(r *Record) error {
+	var errs []error
+	for _, s := range l.sinks {
+		if !s.Enabled(r.Level) {
+			continue
+		}
+		if err := s.Write(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestSinks(t *testing.T) {
+	l := logger.New(logger.LevelTrace)
+	var out, debug, info, errs bytes.Buffer
+	l.SetOutput(&out)
+	l.SetSinks(
+		logger.NewWriterSink(&debug, logger.LevelDebug, nil),
+		logger.NewWriterSink(&info, logger.LevelInfo, logger.JSONEncoder{TimeKey: "-", CallerKey: "-"}),
+		logger.NewWriterSink(&errs, logger.LevelError, logger.TextEncoder{}),
+	)
+
+	t.Run("filter by sink level", func(t *testing.T) {
+		l.Traceln("test Traceln")
+		l.Debugln("test Debugln")
+		l.With("code", 200).Infoln("test Infoln")
+		l.Errorw("test Errorw", "err", "failed")
+
+		assert.NotContains(t, debug.String(), "test Traceln")
+		assert.Contains(t, debug.String(), "[DEBUG] test Debugln\n")
+		assert.Contains(t, debug.String(), "[INFO] test Infoln code=200\n")
+		assert.Contains(t, debug.String(), "[ERROR] test Errorw err=failed\n")
+
+		assert.Equal(t, `{"level":"Info","msg":"test Infoln","code":200}`+"\n"+
+			`{"level":"Error","msg":"test Errorw","err":"failed"}`+"\n", info.String(), "they should be equal")
+		assert.Equal(t, "[ERROR] test Errorw err=failed\n", errs.String(), "they should be equal")
+		assert.Empty(t, out.String(), "should not write to the output")
+	})
+
+	t.Run("logger level", func(t *testing.T) {
+		debug.Reset()
+		l.SetLevel(logger.LevelWarn)
+		l.Infoln("test Infoln")
+		assert.Empty(t, debug.String(), "should be filtered by the logger level")
+		l.SetLevel(logger.LevelTrace)
+	})
+
+	t.Run("set sink level", func(t *testing.T) {
+		errs.Reset()
+		s := logger.NewWriterSink(&errs, logger.LevelError, logger.TextEncoder{})
+		l.SetSinks(s)
+		l.Warnln("test Warnln")
+		s.SetLevel(logger.LevelWarn)
+		l.Warnln("test Warnln again")
+		assert.Equal(t, "[WARN] test Warnln again\n", errs.String(), "they should be equal")
+	})
+
+	t.Run("restore output", func(t *testing.T) {
+		l.SetSinks()
+		l.Infoln("test Infoln")
+		assert.Contains(t, out.String(), "[INFO] test Infoln\n")
+	})
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// The slog levels of the levels which have no equivalent in log/slog.
+const (
+	SlogLevelTrace = slog.Level(-8)
+	SlogLevelFatal = slog.Level(12)
+	SlogLevelPanic = slog.Level(16)
+)
+
+// SlogLevel returns the slog level of the level.
+func (l LogLevel) SlogLevel() slog.Level {
+	switch {
+	case l <= LevelTrace:
+		return SlogLevelTrace
+	case l == LevelDebug:
+		return slog.LevelDebug
+	case l == LevelInfo:
+		return slog.LevelInfo
+	case l == LevelWarn:
+		return slog.LevelWarn
+	case l == LevelError:
+		return slog.LevelError
+	case l == LevelFatal:
+		return SlogLevelFatal
+	default:
+		return SlogLevelPanic
+	}
+}
+
+// FromSlogLevel returns the level of a slog level, slog levels between two
+// levels are rounded down.
+func FromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	case level < SlogLevelFatal:
+		return LevelError
+	case level < SlogLevelPanic:
+		return LevelFatal
+	default:
+		return LevelPanic
+	}
+}
+
+// NewSlogHandler creates a slog.Handler which writes records through l,
+// with the level, encoder and output of l. Records at [SlogLevelFatal] and
+// [SlogLevelPanic] are written, but they neither exit nor panic.
+// The fields extracted from the context of a record by the extractors of l
+// are added to it.
+func NewSlogHandler(l *Logger) slog.Handler {
+	return &slogHandler{l: l}
+}
+
+type slogHandler struct {
+	l      *Logger
+	prefix string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.enabled(FromSlogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, sr slog.Record) error {
+	if !h.l.sample(FromSlogLevel(sr.Level), sr.Message) {
+		return nil
+	}
+	r := Record{
+		Time:    sr.Time,
+		Level:   FromSlogLevel(sr.Level),
+		Message: sr.Message,
+		Fields:  h.l.fields[:len(h.l.fields):len(h.l.fields)],
+		PC:      sr.PC,
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if ctx != nil {
+		r.Fields = append(r.Fields, h.l.contextFields(ctx)...)
+	}
+	sr.Attrs(func(a slog.Attr) bool {
+		r.Fields = appendAttr(r.Fields, h.prefix, a)
+		return true
+	})
+	h.l.write(&r)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.prefix, a)
+	}
+	kv := make([]any, len(fields))
+	for i, f := range fields {
+		kv[i] = f
+	}
+	return &slogHandler{l: h.l.With(kv...), prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{l: h.l, prefix: h.prefix + name + "."}
+}
+
+// appendAttr appends a as fields, the keys of groups are joined by ".".
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
+
+// NewFromSlogHandler creates a new *[Logger] which writes records to h.
+// Its level is LevelTrace, so records are filtered by h.Enabled, and
+// formatted by h instead of an [Encoder].
+func NewFromSlogHandler(h slog.Handler) *Logger {
+	l := New(LevelTrace)
+	l.SetSinks(NewSlogSink(h))
+	return l
+}
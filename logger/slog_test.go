@@ -0,0 +1,64 @@
+package logger_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestSlogLevel(t *testing.T) {
+	levels := []logger.LogLevel{
+		logger.LevelTrace,
+		logger.LevelDebug,
+		logger.LevelInfo,
+		logger.LevelWarn,
+		logger.LevelError,
+		logger.LevelFatal,
+		logger.LevelPanic,
+	}
+	for _, level := range levels {
+		assert.Equal(t, level, logger.FromSlogLevel(level.SlogLevel()), "they should be equal")
+	}
+	assert.Equal(t, logger.LevelInfo, logger.FromSlogLevel(slog.LevelInfo+2), "they should be equal")
+}
+
+func TestSlogHandler(t *testing.T) {
+	l := logger.New(logger.LevelInfo)
+	var got bytes.Buffer
+	l.SetOutput(&got)
+	sl := slog.New(logger.NewSlogHandler(l)).With("service", "api")
+
+	sl.Info("test Info", "code", 200)
+	sl.WithGroup("req").Warn("test Warn", "path", "/users", slog.Group("user", "id", 1))
+	sl.Debug("test Debug")
+	assert.Contains(t, got.String(), "[INFO] test Info service=api code=200\n")
+	assert.Contains(t, got.String(), "[WARN] test Warn service=api req.path=/users req.user.id=1\n")
+	assert.NotContains(t, got.String(), "test Debug")
+
+	// the handler shares the encoder of the logger
+	got.Reset()
+	l.SetEncoder(logger.JSONEncoder{})
+	slog.New(logger.NewSlogHandler(l)).Error("test Error")
+	assert.Contains(t, got.String(), `"level":"Error","msg":"test Error"`)
+}
+
+func TestNewFromSlogHandler(t *testing.T) {
+	var got bytes.Buffer
+	h := slog.NewTextHandler(&got, &slog.HandlerOptions{Level: logger.SlogLevelTrace})
+	l := logger.NewFromSlogHandler(h)
+
+	l.With("service", "api").Infow("test Infow", "code", 200)
+	l.Tracef("test Tracef %s", "with msg")
+	assert.Contains(t, got.String(), `level=INFO msg="test Infow" service=api code=200`)
+	assert.Contains(t, got.String(), `level=DEBUG-4 msg="test Tracef with msg"`)
+
+	// records are filtered by the handler
+	got.Reset()
+	l = logger.NewFromSlogHandler(slog.NewTextHandler(&got, nil))
+	l.Debugln("test Debugln")
+	assert.Empty(t, got.String())
+}
@@ -0,0 +1,291 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A SyslogFormat is the message format of a [SyslogSink].
+type SyslogFormat int
+
+const (
+	// SyslogRFC5424 is the format of RFC 5424.
+	SyslogRFC5424 SyslogFormat = iota
+	// SyslogRFC3164 is the legacy BSD format of RFC 3164.
+	SyslogRFC3164
+)
+
+// A Facility is the syslog facility of the messages of a [SyslogSink].
+// The zero value is FacilityUser.
+type Facility int
+
+// The syslog facilities, their values are not the codes of the facilities,
+// so the zero Facility is unset.
+const (
+	FacilityKern Facility = iota + 1
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFtp
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// code returns the syslog code of the facility, FacilityUser if it is unset.
+func (f Facility) code() int {
+	if f <= 0 {
+		return int(FacilityUser) - 1
+	}
+	return int(f) - 1
+}
+
+// syslogDialTimeout bounds the dials of a SyslogSink, and the redials after a
+// failure are delayed from syslogRedialMin, doubling up to syslogRedialMax.
+const (
+	syslogDialTimeout = 5 * time.Second
+	syslogRedialMin   = time.Second
+	syslogRedialMax   = time.Minute
+)
+
+// errSyslogRedial is returned by the writes before the next redial.
+var errSyslogRedial = errors.New("logger: syslog is disconnected, waiting to redial")
+
+// SyslogSeverity returns the syslog severity of the level, from 0
+// (emergency) to 7 (debug).
+func (l LogLevel) SyslogSeverity() int {
+	switch {
+	case l <= LevelDebug:
+		return 7
+	case l == LevelInfo:
+		return 6
+	case l == LevelWarn:
+		return 4
+	case l == LevelError:
+		return 3
+	case l == LevelFatal:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SyslogOptions configures a [SyslogSink].
+type SyslogOptions struct {
+	// Network and Address are the address of the syslog server, such as
+	// "udp" and "127.0.0.1:514". If Network is empty, the local syslog
+	// socket, such as /dev/log, is used.
+	Network string
+	Address string
+	// Format is the message format, SyslogRFC5424 by default.
+	Format SyslogFormat
+	// Facility is the facility of the messages, FacilityUser by default.
+	Facility Facility
+	// Tag is the application name, the name of the program by default.
+	Tag string
+	// Hostname is the name of the host, os.Hostname() by default.
+	Hostname string
+	// Level is the minimum level of the records written to syslog.
+	Level LogLevel
+}
+
+// A SyslogSink is a [Sink] which sends records to a syslog server. Records
+// are sent one per datagram over UDP and Unix datagram sockets, framed by
+// octet counting, as RFC 6587 describes, over TCP, and terminated by a newline
+// over Unix stream sockets, as local syslog daemons expect.
+//
+// The message of a record is followed by its fields in logfmt.
+type SyslogSink struct {
+	opts  SyslogOptions
+	level *AtomicLevel
+	pid   string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	network string
+	// redial is the time of the next dial after a failure, and delay the
+	// time to wait after the next failure.
+	redial time.Time
+	delay  time.Duration
+}
+
+// localSyslogAddresses are the paths of the local syslog socket.
+var localSyslogAddresses = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// NewSyslogSink connects to the syslog server, and returns a *[SyslogSink]
+// sending records to it.
+func NewSyslogSink(opts SyslogOptions) (*SyslogSink, error) {
+	if opts.Tag == "" {
+		opts.Tag = filepath.Base(os.Args[0])
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	s := &SyslogSink{
+		opts:  opts,
+		level: NewAtomicLevel(opts.Level),
+		pid:   strconv.Itoa(os.Getpid()),
+	}
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// dial connects to the server, it tries the local sockets if no network is set.
+func (s *SyslogSink) dial() error {
+	if s.opts.Network != "" {
+		conn, err := net.DialTimeout(s.opts.Network, s.opts.Address, syslogDialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn, s.network = conn, s.opts.Network
+		return nil
+	}
+
+	var errs []error
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, address := range localSyslogAddresses {
+			conn, err := net.DialTimeout(network, address, syslogDialTimeout)
+			if err == nil {
+				s.conn, s.network = conn, network
+				return nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetLevel sets the minimum level of the sink. It is safe to call while
+// the sink is used.
+func (s *SyslogSink) SetLevel(level LogLevel) {
+	s.level.SetLevel(level)
+}
+
+// Enabled implements [Sink].
+func (s *SyslogSink) Enabled(level LogLevel) bool {
+	return s.level.Level() <= level
+}
+
+// Write implements [Sink], it reconnects once if the connection is broken.
+// After a failed reconnection, the records are dropped until the next
+// reconnection, which is delayed from 1 second up to 1 minute.
+func (s *SyslogSink) Write(r *Record) error {
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	buf.Reset()
+	s.format(buf, r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		if err := s.send(buf.Bytes()); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	now := time.Now()
+	if now.Before(s.redial) {
+		return errSyslogRedial
+	}
+	if err := s.dial(); err != nil {
+		s.delay = min(max(2*s.delay, syslogRedialMin), syslogRedialMax)
+		s.redial = now.Add(s.delay)
+		return err
+	}
+	s.redial, s.delay = time.Time{}, 0
+	return s.send(buf.Bytes())
+}
+
+func (s *SyslogSink) send(msg []byte) error {
+	switch s.network {
+	case "tcp", "tcp4", "tcp6":
+		// octet counting
+		b := strconv.AppendInt(make([]byte, 0, len(msg)+8), int64(len(msg)), 10)
+		b = append(b, ' ')
+		_, err := s.conn.Write(append(b, msg...))
+		return err
+	case "unix":
+		// local daemons split stream messages by newline
+		_, err := s.conn.Write(append(msg[:len(msg):len(msg)], '\n'))
+		return err
+	}
+	_, err := s.conn.Write(msg)
+	return err
+}
+
+// Close closes the connection.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format writes the syslog message of r.
+func (s *SyslogSink) format(buf *bytes.Buffer, r *Record) {
+	pri := s.opts.Facility.code()*8 + r.Level.SyslogSeverity()
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(pri))
+	buf.WriteByte('>')
+
+	hostname := s.opts.Hostname
+	if hostname == "" {
+		hostname = "-"
+	}
+	if s.opts.Format == SyslogRFC3164 {
+		// <PRI>Jan _2 15:04:05 HOSTNAME TAG[PID]: MSG
+		buf.Write(r.Time.AppendFormat(buf.AvailableBuffer(), time.Stamp))
+		buf.WriteByte(' ')
+		buf.WriteString(hostname)
+		buf.WriteByte(' ')
+		buf.WriteString(s.opts.Tag)
+		buf.WriteString("[" + s.pid + "]: ")
+	} else {
+		// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		buf.WriteString("1 ")
+		buf.Write(r.Time.AppendFormat(buf.AvailableBuffer(), "2006-01-02T15:04:05.000000Z07:00"))
+		buf.WriteByte(' ')
+		buf.WriteString(hostname)
+		buf.WriteByte(' ')
+		buf.WriteString(s.opts.Tag)
+		buf.WriteString(" " + s.pid + " - - ")
+	}
+
+	buf.WriteString(r.Message)
+	fields, _ := expandErrors(r.Fields)
+	for _, f := range fields {
+		buf.WriteByte(' ')
+		appendLogfmt(buf, f.Key)
+		buf.WriteByte('=')
+		appendLogfmt(buf, formatValue(f.Value))
+	}
+}
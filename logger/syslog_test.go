@@ -0,0 +1,138 @@
+package logger_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+func TestSyslogSink(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+
+	t.Run("rfc5424 over udp", func(t *testing.T) {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NoError(t, err, "should not be an error")
+		defer pc.Close()
+
+		s, err := logger.NewSyslogSink(logger.SyslogOptions{
+			Network:  "udp",
+			Address:  pc.LocalAddr().String(),
+			Facility: logger.FacilityLocal0,
+			Tag:      "app",
+			Hostname: "host",
+			Level:    logger.LevelInfo,
+		})
+		assert.NoError(t, err, "should not be an error")
+		defer s.Close()
+		l := logger.New(logger.LevelTrace)
+		l.SetSinks(s)
+		l.Debugln("test Debugln")
+		l.Warnw("test Warnw", "user", "jane doe")
+
+		buf := make([]byte, 1024)
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		assert.NoError(t, err, "should not be an error")
+		// local0 * 8 + warning
+		assert.Regexp(t, `^<132>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}\S+ host app `+pid+
+			` - - test Warnw user="jane doe"$`, string(buf[:n]))
+	})
+
+	t.Run("rfc3164 over tcp", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err, "should not be an error")
+		defer ln.Close()
+
+		s, err := logger.NewSyslogSink(logger.SyslogOptions{
+			Network:  "tcp",
+			Address:  ln.Addr().String(),
+			Format:   logger.SyslogRFC3164,
+			Tag:      "app",
+			Hostname: "host",
+		})
+		assert.NoError(t, err, "should not be an error")
+		defer s.Close()
+		l := logger.New(logger.LevelTrace)
+		l.SetSinks(s)
+		l.Errorln("test Errorln")
+
+		conn, err := ln.Accept()
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		r := bufio.NewReader(conn)
+		size, err := r.ReadString(' ')
+		assert.NoError(t, err, "should not be an error")
+		n, err := strconv.Atoi(size[:len(size)-1])
+		assert.NoError(t, err, "should not be an error")
+		msg := make([]byte, n)
+		_, err = io.ReadFull(r, msg)
+		assert.NoError(t, err, "should not be an error")
+		// user, the default facility, * 8 + error
+		assert.Regexp(t, `^<11>\w{3} [ \d]\d \d\d:\d\d:\d\d host app\[`+pid+`\]: test Errorln$`, string(msg))
+	})
+
+	t.Run("rfc3164 over unix stream", func(t *testing.T) {
+		ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "log.sock"))
+		if err != nil {
+			t.Skip("unix sockets are not supported")
+		}
+		defer ln.Close()
+
+		s, err := logger.NewSyslogSink(logger.SyslogOptions{
+			Network:  "unix",
+			Address:  ln.Addr().String(),
+			Format:   logger.SyslogRFC3164,
+			Tag:      "app",
+			Hostname: "host",
+		})
+		assert.NoError(t, err, "should not be an error")
+		defer s.Close()
+		l := logger.New(logger.LevelTrace)
+		l.SetSinks(s)
+		l.Errorln("test Errorln")
+		l.Infoln("test Infoln")
+
+		conn, err := ln.Accept()
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		r := bufio.NewReader(conn)
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err, "should not be an error")
+		assert.Regexp(t, `^<11>\w{3} [ \d]\d \d\d:\d\d:\d\d host app\[`+pid+`\]: test Errorln\n$`, line)
+		line, err = r.ReadString('\n')
+		assert.NoError(t, err, "should not be an error")
+		assert.Regexp(t, `^<14>.* test Infoln\n$`, line)
+	})
+
+	t.Run("redial backoff", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err, "should not be an error")
+		s, err := logger.NewSyslogSink(logger.SyslogOptions{Network: "tcp", Address: ln.Addr().String()})
+		assert.NoError(t, err, "should not be an error")
+		defer s.Close()
+		conn, err := ln.Accept()
+		assert.NoError(t, err, "should not be an error")
+		conn.Close()
+		ln.Close()
+
+		// a write to the closed connection may succeed before the failure is noticed
+		for i := 0; i < 10 && err == nil; i++ {
+			err = s.Write(&logger.Record{Message: "test Write"})
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Error(t, err, "should be an error")
+		err = s.Write(&logger.Record{Message: "test Write"})
+		assert.ErrorContains(t, err, "waiting to redial")
+	})
+}
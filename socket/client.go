@@ -0,0 +1,424 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+// A ConnState is the connection state of a [Client].
+type ConnState int
+
+const (
+	StateConnecting ConnState = iota
+	StateConnected
+	StateDisconnected
+	StateClosed
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "Connecting"
+	case StateConnected:
+		return "Connected"
+	case StateDisconnected:
+		return "Disconnected"
+	case StateClosed:
+		return "Closed"
+	}
+	return "ConnState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// A QueuePolicy decides what the Client does with new data when its queue is full.
+type QueuePolicy int
+
+const (
+	// DropOldest drops the oldest queued data to make room.
+	DropOldest QueuePolicy = iota
+	// DropNewest drops the new data.
+	DropNewest
+	// Block waits until there is room in the queue.
+	Block
+)
+
+// A Client keeps a socket connection to a server, and sends queued data to it.
+//
+// When the connection fails, the Client reconnects with exponential backoff
+// and jitter. Data which is not sent yet stays in a bounded queue.
+type Client struct {
+	network string
+	address string
+	opts    options
+	logger  *logger.Logger
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue [][]byte
+	// inflight is true while the head of the queue is written, it is neither
+	// dropped nor counted in the queue size.
+	inflight bool
+	dropped  uint64
+	closed   bool
+	state    ConnState
+	conn     net.Conn
+
+	closing     chan struct{}
+	closeOnce   sync.Once
+	abandon     chan struct{}
+	abandonOnce sync.Once
+	done        chan struct{}
+}
+
+// NewClient creates a *[Client], and starts connecting to the server in background.
+// Dial errors are reported to the handler set by [WithErrorHandler].
+func NewClient(network, address string, opts ...Option) *Client {
+	c := newClient(network, address, opts)
+	go c.run(nil)
+	return c
+}
+
+// DialClient connects to the server, and returns a *[Client] using the connection.
+// Unlike [NewClient], it returns the error of the first dial, which wraps [ErrDial].
+func DialClient(network, address string, opts ...Option) (*Client, error) {
+	c := newClient(network, address, opts)
+	c.logger.Debugf("run socket client to %s:%s", network, address)
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		err = opError(ErrDial, network, address, err)
+		c.logger.Errorf("%s", err)
+		return nil, err
+	}
+	go c.run(conn)
+	return c, nil
+}
+
+func newClient(network, address string, opts []Option) *Client {
+	c := &Client{
+		network: network,
+		address: address,
+		opts:    newOptions(opts),
+		closing: make(chan struct{}),
+		abandon: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.logger = c.opts.loggerFor(network)
+	return c
+}
+
+// Send queues data to be sent to the server.
+func (c *Client) Send(data []byte) error {
+	dropped, err := c.push(data)
+	// the error handler may call the methods of c, so it is called unlocked
+	if dropped != nil {
+		c.reportError(dropped)
+	}
+	return err
+}
+
+// push queues data, it returns the error to report if data is dropped.
+func (c *Client) push(data []byte) (dropped, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.opts.queuePolicy == Block {
+		for !c.closed && c.pending() >= c.opts.queueSize {
+			c.cond.Wait()
+		}
+	}
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if c.pending() >= c.opts.queueSize {
+		c.dropped++
+		if c.opts.queuePolicy == DropNewest {
+			return opError(ErrQueueFull, c.network, c.address, errors.New("drop newest data")), ErrQueueFull
+		}
+		dropped = opError(ErrQueueFull, c.network, c.address, errors.New("drop oldest data"))
+		// the data being written is not dropped
+		oldest := 0
+		if c.inflight {
+			oldest = 1
+		}
+		c.queue = append(c.queue[:oldest], c.queue[oldest+1:]...)
+	}
+	c.queue = append(c.queue, data)
+	c.cond.Broadcast()
+	return dropped, nil
+}
+
+// pending returns the number of queued data which are not being written.
+func (c *Client) pending() int {
+	if c.inflight {
+		return len(c.queue) - 1
+	}
+	return len(c.queue)
+}
+
+// State returns the current connection state.
+func (c *Client) State() ConnState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Queued returns the number of data waiting to be sent.
+func (c *Client) Queued() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queue)
+}
+
+// Dropped returns the number of data dropped because the queue was full.
+func (c *Client) Dropped() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+// Close stops accepting new data, and waits for queued data to be sent. If ctx
+// is done first, the remaining data is dropped and ctx.Err() is returned.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.cond.Broadcast()
+	c.mu.Unlock()
+	c.closeOnce.Do(func() { close(c.closing) })
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.abandonOnce.Do(func() { close(c.abandon) })
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.cond.Broadcast()
+	c.mu.Unlock()
+	<-c.done
+	if c.Queued() > 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// run keeps the connection until the client is finished, conn is the first
+// connection if it is already dialed.
+func (c *Client) run(conn net.Conn) {
+	defer close(c.done)
+	defer c.setState(StateClosed)
+
+	for attempt := 0; ; attempt++ {
+		if c.finished() {
+			return
+		}
+		var err error
+		if conn == nil {
+			c.setState(StateConnecting)
+			c.logger.Debugf("run socket client to %s:%s", c.network, c.address)
+			conn, err = c.dial()
+		}
+		if err != nil {
+			c.reportError(opError(ErrDial, c.network, c.address, err))
+			conn = nil
+			if !c.sleep(c.backoff(attempt)) {
+				return
+			}
+			continue
+		}
+		c.logger.Infof("dial: <%s>", conn.RemoteAddr().String())
+		c.setConn(conn)
+		c.setState(StateConnected)
+
+		start := time.Now()
+		sent, err := c.writeLoop(conn)
+		c.setConn(nil)
+		conn.Close()
+		conn = nil
+		if err == nil {
+			return
+		}
+		c.reportError(opError(ErrWrite, c.network, c.address, err))
+		c.setState(StateDisconnected)
+		// a connection which worked resets the backoff, unlike one which
+		// is dropped right away, such as by an overloaded server
+		if sent || time.Since(start) >= c.opts.backoffMax {
+			attempt = 0
+		}
+		if !c.sleep(c.backoff(attempt)) {
+			return
+		}
+	}
+}
+
+func (c *Client) dial() (net.Conn, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.abandon:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var d net.Dialer
+	return d.DialContext(ctx, c.network, c.address)
+}
+
+// writeLoop sends queued data on conn, it reports whether any data is sent.
+// It returns a nil error when the client is closed and there is nothing left
+// to send.
+func (c *Client) writeLoop(conn net.Conn) (sent bool, err error) {
+	// the data sent by the server, such as a greeting, is discarded, a read
+	// only fails when the connection is broken, so it is noticed without
+	// waiting for a write.
+	broken := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, conn)
+		c.mu.Lock()
+		close(broken)
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	}()
+
+	for {
+		data, err := c.next(broken)
+		if data == nil {
+			return sent, err
+		}
+		if c.opts.framer != nil {
+			err = c.opts.framer.WriteFrame(conn, data)
+		} else {
+			_, err = conn.Write(data)
+		}
+		if err != nil {
+			c.release()
+			c.logger.Debugf("send data to %s:%s failed: %s: %s", c.network, c.address, err, data)
+			return sent, err
+		}
+		sent = true
+		c.pop()
+		c.logger.Infof("send data to %s:%s success", c.network, c.address)
+		c.logger.Debugf("send data to %s:%s success: %s", c.network, c.address, data)
+	}
+}
+
+// next waits for the data at the head of the queue. It returns nil data when
+// the client is finished or the connection is broken.
+func (c *Client) next(broken <-chan struct{}) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.queue) == 0 && !c.closed && !isDone(broken) {
+		c.cond.Wait()
+	}
+	switch {
+	case isDone(c.abandon):
+		return nil, nil
+	case isDone(broken):
+		return nil, net.ErrClosed
+	case len(c.queue) == 0:
+		return nil, nil
+	}
+	c.inflight = true
+	return c.queue[0], nil
+}
+
+// pop removes the head of the queue once it is sent.
+func (c *Client) pop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inflight = false
+	c.queue[0] = nil
+	c.queue = c.queue[1:]
+	c.cond.Broadcast()
+}
+
+// release keeps the head of the queue when it fails to be sent, so it is
+// sent again on the next connection.
+func (c *Client) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inflight = false
+}
+
+func (c *Client) finished() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return isDone(c.abandon) || (c.closed && len(c.queue) == 0)
+}
+
+func (c *Client) setConn(conn net.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn = conn
+}
+
+func (c *Client) reportError(err error) {
+	c.logger.Errorf("%s", err)
+	if c.opts.errorHandler != nil {
+		c.opts.errorHandler(err)
+	}
+}
+
+func (c *Client) setState(state ConnState) {
+	c.mu.Lock()
+	changed := c.state != state
+	c.state = state
+	c.mu.Unlock()
+	if changed && c.opts.stateHandler != nil {
+		c.opts.stateHandler(state)
+	}
+}
+
+// sleep waits for d, it returns false if the client is finished meanwhile.
+func (c *Client) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	closing := c.closing
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case <-c.abandon:
+			return false
+		case <-closing:
+			if c.finished() {
+				return false
+			}
+			closing = nil
+		}
+	}
+}
+
+// backoff returns the delay before the next dial, it doubles on each attempt
+// up to the maximum, and is jittered into [delay/2, delay].
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.opts.backoffMax
+	if attempt < 32 && c.opts.backoffMin<<attempt < c.opts.backoffMax {
+		delay = c.opts.backoffMin << attempt
+	}
+	if delay <= 1 {
+		return delay
+	}
+	return delay/2 + rand.N(delay/2)
+}
+
+func isDone(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
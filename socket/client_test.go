@@ -0,0 +1,226 @@
+package socket_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/socket"
+)
+
+func TestClientReconnect(t *testing.T) {
+	// reserve an address, so the server can be restarted on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "should not be an error")
+	address := l.Addr().String()
+	l.Close()
+
+	states := make(chan socket.ConnState, 16)
+	c := socket.NewClient("tcp", address,
+		socket.WithFramer(socket.NewLineFramer()),
+		socket.WithBackoff(time.Millisecond, 10*time.Millisecond),
+		socket.WithStateHandler(func(s socket.ConnState) { states <- s }))
+
+	// data is queued while the server is down
+	assert.NoError(t, c.Send([]byte("first")), "should not be an error")
+
+	for _, want := range []string{"first", "second"} {
+		ch := make(chan []byte, 1)
+		s := socket.NewTCPServer(address, 1024, ch, socket.WithFramer(socket.NewLineFramer()))
+		go s.ListenAndServe()
+		if want == "second" {
+			assert.NoError(t, c.Send([]byte(want)), "should not be an error")
+		}
+		select {
+		case got := <-ch:
+			assert.Equal(t, []byte(want), got, "they should be equal")
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for data")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		s.Shutdown(ctx)
+		cancel()
+		waitState(t, states, socket.StateDisconnected)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, c.Close(ctx), "should not be an error")
+	assert.ErrorIs(t, c.Send([]byte("closed")), socket.ErrClientClosed)
+	assert.Equal(t, socket.StateClosed, c.State(), "they should be equal")
+}
+
+func waitState(t *testing.T, states <-chan socket.ConnState, want socket.ConnState) {
+	t.Helper()
+	for {
+		select {
+		case got := <-states:
+			if got == want {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for state %s", want)
+		}
+	}
+}
+
+func TestClientReconnectBackoff(t *testing.T) {
+	accept := func(t *testing.T, handle func(net.Conn)) (string, *atomic.Int32) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err, "should not be an error")
+		t.Cleanup(func() { l.Close() })
+		var accepted atomic.Int32
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				accepted.Add(1)
+				handle(conn)
+			}
+		}()
+		return l.Addr().String(), &accepted
+	}
+
+	t.Run("test greeting", func(t *testing.T) {
+		address, accepted := accept(t, func(conn net.Conn) {
+			conn.Write([]byte("hello client\n"))
+			// closed when the client closes the connection
+			go func() {
+				io.Copy(io.Discard, conn)
+				conn.Close()
+			}()
+		})
+		c := socket.NewClient("tcp", address, socket.WithNopLogger(),
+			socket.WithBackoff(10*time.Millisecond, 20*time.Millisecond))
+		time.Sleep(300 * time.Millisecond)
+		c.Close(context.Background())
+		assert.Equal(t, int32(1), accepted.Load(), "should keep the connection")
+	})
+
+	t.Run("test dropped connections", func(t *testing.T) {
+		address, accepted := accept(t, func(conn net.Conn) { conn.Close() })
+		c := socket.NewClient("tcp", address, socket.WithNopLogger(),
+			socket.WithBackoff(50*time.Millisecond, 100*time.Millisecond))
+		time.Sleep(300 * time.Millisecond)
+		c.Close(context.Background())
+		assert.Less(t, accepted.Load(), int32(15), "should back off before redialing")
+	})
+}
+
+func TestClientQueue(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "should not be an error")
+	address := l.Addr().String()
+	l.Close()
+
+	t.Run("test drop newest", func(t *testing.T) {
+		c := socket.NewClient("tcp", address, socket.WithQueue(2, socket.DropNewest))
+		assert.NoError(t, c.Send([]byte("1")), "should not be an error")
+		assert.NoError(t, c.Send([]byte("2")), "should not be an error")
+		assert.ErrorIs(t, c.Send([]byte("3")), socket.ErrQueueFull)
+		assert.Equal(t, 2, c.Queued(), "they should be equal")
+		assert.Equal(t, uint64(1), c.Dropped(), "they should be equal")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, c.Close(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("test error handler calling the client", func(t *testing.T) {
+		var c *socket.Client
+		var dropped uint64
+		c = socket.NewClient("tcp", address, socket.WithQueue(1, socket.DropNewest),
+			socket.WithBackoff(time.Hour, time.Hour),
+			socket.WithErrorHandler(func(err error) {
+				if errors.Is(err, socket.ErrQueueFull) {
+					dropped = c.Dropped()
+				}
+			}))
+		assert.NoError(t, c.Send([]byte("1")), "should not be an error")
+		assert.ErrorIs(t, c.Send([]byte("2")), socket.ErrQueueFull)
+		assert.Equal(t, uint64(1), dropped, "they should be equal")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		c.Close(ctx)
+	})
+
+	t.Run("test drop oldest", func(t *testing.T) {
+		c := socket.NewClient("tcp", address, socket.WithQueue(2, socket.DropOldest))
+		assert.NoError(t, c.Send([]byte("1")), "should not be an error")
+		assert.NoError(t, c.Send([]byte("2")), "should not be an error")
+		assert.NoError(t, c.Send([]byte("3")), "should not be an error")
+		assert.Equal(t, 2, c.Queued(), "they should be equal")
+		assert.Equal(t, uint64(1), c.Dropped(), "they should be equal")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, c.Close(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("test drop oldest while sending", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err, "should not be an error")
+		defer l.Close()
+
+		states := make(chan socket.ConnState, 16)
+		c := socket.NewClient("tcp", l.Addr().String(), socket.WithQueue(3, socket.DropOldest),
+			socket.WithStateHandler(func(s socket.ConnState) { states <- s }))
+		conn, err := l.Accept()
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+		waitState(t, states, socket.StateConnected)
+
+		// the server does not read yet, so the write of A blocks
+		a := bytes.Repeat([]byte("A"), 8<<20)
+		assert.NoError(t, c.Send(a), "should not be an error")
+		time.Sleep(100 * time.Millisecond)
+		// A is not counted in the queue, nor dropped for E
+		for _, data := range []string{"B", "C", "D", "E"} {
+			assert.NoError(t, c.Send([]byte(data)), "should not be an error")
+		}
+		assert.Equal(t, uint64(1), c.Dropped(), "they should be equal")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		errCh := make(chan error, 1)
+		go func() { errCh <- c.Close(ctx) }()
+		got, err := io.ReadAll(io.LimitReader(conn, int64(len(a)+3)))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, string(a)+"CDE", string(got), "should drop B, not A which is in flight")
+		assert.NoError(t, <-errCh, "should not be an error")
+	})
+
+	t.Run("test invalid size", func(t *testing.T) {
+		assert.Panics(t, func() { socket.WithQueue(0, socket.DropOldest) }, "should panic")
+	})
+}
+
+func TestDialClient(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "should not be an error")
+	address := l.Addr().String()
+	l.Close()
+
+	c, err := socket.DialClient("tcp", address)
+	assert.Nil(t, c)
+	assert.ErrorIs(t, err, socket.ErrDial)
+
+	errs := make(chan error, 1)
+	c = socket.NewClient("tcp", address,
+		socket.WithBackoff(time.Hour, time.Hour),
+		socket.WithErrorHandler(func(err error) { errs <- err }))
+	assert.ErrorIs(t, <-errs, socket.ErrDial)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, c.Close(ctx), "should not be an error")
+}
@@ -0,0 +1,40 @@
+package socket
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors reported by package socket, they can be matched with errors.Is.
+// Startup errors are returned by Listen and DialClient, errors which happen
+// later on a connection are passed to the handler set by [WithErrorHandler].
+var (
+	// ErrListen wraps errors of listening on a socket.
+	ErrListen = errors.New("socket: listen failed")
+	// ErrAccept wraps errors of accepting a connection.
+	ErrAccept = errors.New("socket: accept failed")
+	// ErrRead wraps errors of reading data from a connection.
+	ErrRead = errors.New("socket: read failed")
+	// ErrDial wraps errors of connecting to a server.
+	ErrDial = errors.New("socket: dial failed")
+	// ErrWrite wraps errors of sending data to a server.
+	ErrWrite = errors.New("socket: write failed")
+	// ErrNotListening is returned by the Server's Serve method when Listen was not called.
+	ErrNotListening = errors.New("socket: server is not listening")
+	// ErrServerClosed is returned by the Server's Serve and ListenAndServe methods after a call to Shutdown.
+	ErrServerClosed = errors.New("socket: server closed")
+	// ErrClientClosed is returned by the Client's Send method after a call to Close.
+	ErrClientClosed = errors.New("socket: client closed")
+	// ErrQueueFull is reported when the Client queue is full and data is dropped.
+	ErrQueueFull = errors.New("socket: client queue full")
+	// ErrFrameTooLarge is returned when a frame exceeds the maximum frame size.
+	ErrFrameTooLarge = errors.New("socket: frame too large")
+)
+
+// opError wraps err with kind, and the network address it happened on.
+func opError(kind error, network, address string, err error) error {
+	if err == nil {
+		return fmt.Errorf("%w: %s:%s", kind, network, address)
+	}
+	return fmt.Errorf("%w: %s:%s: %w", kind, network, address, err)
+}
@@ -0,0 +1,171 @@
+package socket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// A Framer splits a byte stream into logical messages.
+type Framer interface {
+	// ReadFrame reads the next message from r. It returns [ErrFrameTooLarge]
+	// if the message is longer than max bytes, a max <= 0 means no limit.
+	// It returns io.EOF at the end of the stream, and io.ErrUnexpectedEOF if
+	// the stream ends in the middle of a message.
+	ReadFrame(r *bufio.Reader, max int) ([]byte, error)
+	// WriteFrame writes p to w as one message.
+	WriteFrame(w io.Writer, p []byte) error
+}
+
+// NewLineFramer creates a [Framer] for newline-delimited messages.
+// A trailing "\r" is removed from read messages.
+func NewLineFramer() Framer {
+	return lineFramer{delimiterFramer{delim: []byte("\n")}}
+}
+
+type lineFramer struct {
+	delimiterFramer
+}
+
+func (f lineFramer) ReadFrame(r *bufio.Reader, max int) ([]byte, error) {
+	limit := max
+	if max > 0 {
+		// leave room for the "\r"
+		limit++
+	}
+	frame, err := f.delimiterFramer.ReadFrame(r, limit)
+	if err != nil {
+		return nil, err
+	}
+	frame = bytes.TrimSuffix(frame, []byte("\r"))
+	if max > 0 && len(frame) > max {
+		return nil, ErrFrameTooLarge
+	}
+	return frame, nil
+}
+
+// NewDelimiterFramer creates a [Framer] for messages terminated by delim.
+func NewDelimiterFramer(delim []byte) Framer {
+	if len(delim) == 0 {
+		panic("socket: empty frame delimiter")
+	}
+	return delimiterFramer{delim: bytes.Clone(delim)}
+}
+
+type delimiterFramer struct {
+	delim []byte
+}
+
+func (f delimiterFramer) ReadFrame(r *bufio.Reader, max int) ([]byte, error) {
+	last := f.delim[len(f.delim)-1]
+	var frame []byte
+	for {
+		chunk, err := r.ReadSlice(last)
+		frame = append(frame, chunk...)
+		if err == nil && bytes.HasSuffix(frame, f.delim) {
+			frame = frame[:len(frame)-len(f.delim)]
+			if max > 0 && len(frame) > max {
+				return nil, ErrFrameTooLarge
+			}
+			return frame, nil
+		}
+		if max > 0 && len(frame) > max+len(f.delim) {
+			return nil, ErrFrameTooLarge
+		}
+		if err == io.EOF && len(frame) > 0 {
+			// the peer stopped in the middle of a message
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return nil, err
+		}
+	}
+}
+
+func (f delimiterFramer) WriteFrame(w io.Writer, p []byte) error {
+	buf := make([]byte, 0, len(p)+len(f.delim))
+	buf = append(buf, p...)
+	buf = append(buf, f.delim...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// A LengthPrefix is the encoding of the length header of a length-prefixed frame.
+type LengthPrefix int
+
+const (
+	// LengthUint16 is a big-endian uint16 header.
+	LengthUint16 LengthPrefix = iota
+	// LengthUint32 is a big-endian uint32 header.
+	LengthUint32
+	// LengthVarint is an unsigned varint header, as encoding/binary.
+	LengthVarint
+)
+
+// NewLengthPrefixFramer creates a [Framer] for messages preceded by their length.
+func NewLengthPrefixFramer(prefix LengthPrefix) Framer {
+	return lengthPrefixFramer{prefix: prefix}
+}
+
+type lengthPrefixFramer struct {
+	prefix LengthPrefix
+}
+
+func (f lengthPrefixFramer) ReadFrame(r *bufio.Reader, max int) ([]byte, error) {
+	var n uint64
+	switch f.prefix {
+	case LengthUint16:
+		var h [2]byte
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(h[:]))
+	case LengthUint32:
+		var h [4]byte
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return nil, err
+		}
+		n = uint64(binary.BigEndian.Uint32(h[:]))
+	default:
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		n = v
+	}
+	if (max > 0 && n > uint64(max)) || n > math.MaxInt32 {
+		return nil, ErrFrameTooLarge
+	}
+
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (f lengthPrefixFramer) WriteFrame(w io.Writer, p []byte) error {
+	var buf []byte
+	switch f.prefix {
+	case LengthUint16:
+		if len(p) > math.MaxUint16 {
+			return ErrFrameTooLarge
+		}
+		buf = binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(p)), uint16(len(p)))
+	case LengthUint32:
+		if uint64(len(p)) > math.MaxUint32 {
+			return ErrFrameTooLarge
+		}
+		buf = binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(p)), uint32(len(p)))
+	default:
+		buf = binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(p)), uint64(len(p)))
+	}
+	buf = append(buf, p...)
+	_, err := w.Write(buf)
+	return err
+}
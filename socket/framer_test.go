@@ -0,0 +1,80 @@
+package socket_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/socket"
+)
+
+func TestFramer(t *testing.T) {
+	framers := map[string]socket.Framer{
+		"line":      socket.NewLineFramer(),
+		"delimiter": socket.NewDelimiterFramer([]byte("\r\n\r\n")),
+		"uint16":    socket.NewLengthPrefixFramer(socket.LengthUint16),
+		"uint32":    socket.NewLengthPrefixFramer(socket.LengthUint32),
+		"varint":    socket.NewLengthPrefixFramer(socket.LengthVarint),
+	}
+	msgs := [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte("x"), 100)}
+
+	for name, f := range framers {
+		t.Run("test "+name+" framer", func(t *testing.T) {
+			var stream bytes.Buffer
+			for _, msg := range msgs {
+				assert.NoError(t, f.WriteFrame(&stream, msg), "should not be an error")
+			}
+			// a small reader buffer makes frames span several reads
+			r := bufio.NewReaderSize(bytes.NewReader(stream.Bytes()), 16)
+			for _, msg := range msgs {
+				got, err := f.ReadFrame(r, 100)
+				assert.NoError(t, err, "should not be an error")
+				assert.Equal(t, msg, got, "they should be equal")
+			}
+			_, err := f.ReadFrame(r, 100)
+			assert.ErrorIs(t, err, io.EOF)
+
+			// the stream ends in the middle of a message
+			truncated := stream.Bytes()[:stream.Len()-1]
+			r = bufio.NewReader(bytes.NewReader(truncated))
+			for range msgs[:len(msgs)-1] {
+				_, err = f.ReadFrame(r, 100)
+				assert.NoError(t, err, "should not be an error")
+			}
+			_, err = f.ReadFrame(r, 100)
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+			stream.Reset()
+			assert.NoError(t, f.WriteFrame(&stream, bytes.Repeat([]byte("x"), 101)), "should not be an error")
+			_, err = f.ReadFrame(bufio.NewReader(&stream), 100)
+			assert.ErrorIs(t, err, socket.ErrFrameTooLarge)
+		})
+	}
+}
+
+func TestServerFramer(t *testing.T) {
+	ch := make(chan []byte, 3)
+	s := socket.NewTCPServer("127.0.0.1:0", 1024, ch,
+		socket.WithFramer(socket.NewLineFramer()), socket.WithMaxFrameSize(8))
+	go s.ListenAndServe()
+	defer s.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", waitAddr(t, s).String())
+	assert.NoError(t, err, "should not be an error")
+	defer conn.Close()
+	_, err = conn.Write([]byte("a\nbc\r\ndef\ntoo long line\nlost\n"))
+	assert.NoError(t, err, "should not be an error")
+
+	assert.Equal(t, []byte("a"), <-ch, "they should be equal")
+	assert.Equal(t, []byte("bc"), <-ch, "they should be equal")
+	assert.Equal(t, []byte("def"), <-ch, "they should be equal")
+	// the connection is dropped after the frame exceeds the maximum size
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err, "should be an error")
+	assert.Len(t, ch, 0)
+}
@@ -0,0 +1,159 @@
+package socket
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+// DefaultShutdownTimeout is the time the context variants of the Run functions
+// wait for in-flight connections to drain before closing them.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// DefaultMaxFrameSize is the maximum size of a message read by a [Framer].
+const DefaultMaxFrameSize = 64 * 1024
+
+// DefaultQueueSize is the number of data a [Client] keeps while it is not connected.
+const DefaultQueueSize = 1024
+
+type options struct {
+	shutdownTimeout time.Duration
+	framer          Framer
+	maxFrameSize    int
+
+	queueSize    int
+	queuePolicy  QueuePolicy
+	backoffMin   time.Duration
+	backoffMax   time.Duration
+	stateHandler func(ConnState)
+
+	errorHandler  func(error)
+	logger        *logger.Logger
+	sharedChannel bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		shutdownTimeout: DefaultShutdownTimeout,
+		maxFrameSize:    DefaultMaxFrameSize,
+		queueSize:       DefaultQueueSize,
+		queuePolicy:     DropOldest,
+		backoffMin:      100 * time.Millisecond,
+		backoffMax:      30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// loggerFor returns the logger set by WithLogger, or the logger of package
+// socket for network, named "socket.<network>", such as "socket.tcp", see
+// [logger.SetLevelSpec].
+func (o *options) loggerFor(network string) *logger.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return logger.Named("socket").Named(network)
+}
+
+// An Option configures a [Server] or a [Client].
+type Option func(*options)
+
+// WithShutdownTimeout sets how long the context variants of the Run functions
+// wait for in-flight connections to drain after the context is done, and how
+// long RunSocketClient waits for queued data to be sent after the channel is closed.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithFramer splits the stream of tcp and unix connections into messages by f,
+// so each channel send is exactly one message. Without a framer, each send is
+// whatever a single read returns. It has no effect on udp servers.
+func WithFramer(f Framer) Option {
+	return func(o *options) {
+		o.framer = f
+	}
+}
+
+// WithMaxFrameSize sets the maximum size of a message read by the framer,
+// a connection sending a larger message is dropped. The default is [DefaultMaxFrameSize].
+func WithMaxFrameSize(n int) Option {
+	return func(o *options) {
+		o.maxFrameSize = n
+	}
+}
+
+// WithQueue sets the size of the [Client] queue, not counting the data being
+// written, and what to do when it is full.
+// The default is [DefaultQueueSize] and [DropOldest]. It panics if size is less than 1.
+func WithQueue(size int, policy QueuePolicy) Option {
+	if size < 1 {
+		panic("socket: queue size must be at least 1")
+	}
+	return func(o *options) {
+		o.queueSize = size
+		o.queuePolicy = policy
+	}
+}
+
+// WithSharedChannel makes a [Server] leave its channel open on Shutdown, so
+// several servers can send to the same channel. The caller closes the channel
+// once every server sending to it is shut down.
+func WithSharedChannel() Option {
+	return func(o *options) {
+		o.sharedChannel = true
+	}
+}
+
+// WithBackoff sets the minimum and maximum delay between dials of a [Client].
+// The default is 100ms to 30s.
+func WithBackoff(min, max time.Duration) Option {
+	return func(o *options) {
+		o.backoffMin = min
+		o.backoffMax = max
+	}
+}
+
+// WithStateHandler sets a function called when the connection state of a [Client] changes.
+func WithStateHandler(f func(ConnState)) Option {
+	return func(o *options) {
+		o.stateHandler = f
+	}
+}
+
+// WithErrorHandler sets a function called with errors which happen after startup,
+// such as a failed read on a connection of a [Server], or a failed dial of a [Client].
+// The errors wrap one of the errors of package socket, such as [ErrRead].
+// The function must not block, it is called from the connection goroutines.
+func WithErrorHandler(f func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = f
+	}
+}
+
+// WithLogger sets the logger of a [Server] or a [Client]. By default, they log
+// through the named logger "socket.<network>" of the standard logger, such as "socket.tcp".
+// The named logger is created with the Server or the Client, so it follows the
+// level and output of the standard logger, but copies the rest of its
+// configuration, such as [logger.SetRedactor], [logger.SetEncoder] and
+// [logger.SetSinks], which must be called before.
+func WithLogger(l *logger.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithSlogLogger sets a *slog.Logger as the logger of a [Server] or a [Client].
+func WithSlogLogger(l *slog.Logger) Option {
+	return WithLogger(logger.NewFromSlogHandler(l.Handler()))
+}
+
+// WithNopLogger discards the logs of a [Server] or a [Client], errors are
+// still reported to the handler set by [WithErrorHandler].
+func WithNopLogger() Option {
+	return WithLogger(logger.NewNop())
+}
@@ -0,0 +1,371 @@
+package socket
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+)
+
+// A Server listens on a socket, and sends received data to a channel.
+//
+// By default, the Server must be the only sender on its channel: the channel
+// is closed by Shutdown once every connection goroutine has returned. Servers
+// sharing a channel are created with [WithSharedChannel].
+type Server struct {
+	network string
+	address string
+	mtu     int
+	ch      chan<- []byte
+	opts    options
+	logger  *logger.Logger
+
+	mu       sync.Mutex
+	listener io.Closer
+	conns    map[net.Conn]struct{}
+	shutdown bool
+	wg       sync.WaitGroup
+
+	quit      chan struct{}
+	quitOnce  sync.Once
+	closeOnce sync.Once
+}
+
+func newServer(network, address string, mtu int, ch chan<- []byte, opts []Option) *Server {
+	s := &Server{
+		network: network,
+		address: address,
+		mtu:     mtu,
+		ch:      ch,
+		opts:    newOptions(opts),
+		conns:   make(map[net.Conn]struct{}),
+		quit:    make(chan struct{}),
+	}
+	s.logger = s.opts.loggerFor(network)
+	return s
+}
+
+// NewTCPServer creates a *[Server] which listens a tcp socket. It must be the
+// only sender on ch, which is closed on Shutdown, unless [WithSharedChannel] is set.
+func NewTCPServer(address string, mtu int, ch chan<- []byte, opts ...Option) *Server {
+	return newServer("tcp", address, mtu, ch, opts)
+}
+
+// NewUDPServer creates a *[Server] which listens an udp socket. It must be the
+// only sender on ch, which is closed on Shutdown, unless [WithSharedChannel] is set.
+func NewUDPServer(address string, mtu int, ch chan<- []byte, opts ...Option) *Server {
+	return newServer("udp", address, mtu, ch, opts)
+}
+
+// NewUnixServer creates a *[Server] which listens an unix domain socket. It must be
+// the only sender on ch, which is closed on Shutdown, unless [WithSharedChannel] is set.
+func NewUnixServer(address string, dataLength int, ch chan<- []byte, opts ...Option) *Server {
+	return newServer("unix", address, dataLength, ch, opts)
+}
+
+// Listen listens on the server address. Errors are returned synchronously,
+// and wrap [ErrListen].
+func (s *Server) Listen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return ErrServerClosed
+	}
+
+	s.logger.Debugf("run %s server at %s", s.network, s.address)
+	var l io.Closer
+	var err error
+	if s.network == "udp" {
+		l, err = net.ListenPacket(s.network, s.address)
+	} else {
+		l, err = net.Listen(s.network, s.address)
+	}
+	if err != nil {
+		err = opError(ErrListen, s.network, s.address, err)
+		s.logger.Errorf("%s", err)
+		return err
+	}
+	s.listener = l
+	s.logger.Infof("listen: <%s>", s.addr().String())
+	return nil
+}
+
+// Serve sends received data to channel until Shutdown is called. Listen must
+// be called before. It always returns a non-nil error. After Shutdown, the
+// returned error is [ErrServerClosed].
+func (s *Server) Serve() error {
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	l := s.listener
+	if l == nil {
+		s.mu.Unlock()
+		return ErrNotListening
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+
+	if pc, ok := l.(net.PacketConn); ok {
+		return s.servePacket(pc)
+	}
+	return s.serveStream(l.(net.Listener))
+}
+
+// ListenAndServe calls Listen, then Serve.
+func (s *Server) ListenAndServe() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	return s.Serve()
+}
+
+// Addr returns the listening address of the server, or nil if it is not listening.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr()
+}
+
+func (s *Server) addr() net.Addr {
+	switch l := s.listener.(type) {
+	case net.Listener:
+		return l.Addr()
+	case net.PacketConn:
+		return l.LocalAddr()
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the server. It stops accepting new data, and
+// stops reading the connections, then waits for the data which is read already
+// to be sent to the channel. If ctx is done first, remaining connections are
+// closed and ctx.Err() is returned. In both cases the channel
+// is closed once all connection goroutines have returned, unless
+// [WithSharedChannel] is set.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shutdown = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	// interrupt the connections waiting for data, the data which is read
+	// already is still sent to the channel
+	for c := range s.conns {
+		c.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		s.closeConns()
+		<-done
+	}
+	if !s.opts.sharedChannel {
+		s.closeOnce.Do(func() { close(s.ch) })
+	}
+	return err
+}
+
+func (s *Server) servePacket(conn net.PacketConn) error {
+	defer conn.Close()
+	for {
+		// must be in for loop, so channel will get the same slice
+		buf := make([]byte, s.mtu)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if s.shuttingDown() {
+				return ErrServerClosed
+			}
+			err = opError(ErrRead, s.network, s.address, err)
+			s.reportError(err)
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			continue
+		}
+		s.logger.Infof("received data from %s", addr.String())
+		s.logger.Debugf("received data from %s: %s", addr.String(), buf[:n])
+		if !s.send(buf[:n]) {
+			return ErrServerClosed
+		}
+	}
+}
+
+func (s *Server) serveStream(l net.Listener) error {
+	defer l.Close()
+	// tempDelay is how long to sleep after a failed accept, as net/http does
+	var tempDelay time.Duration
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.shuttingDown() {
+				return ErrServerClosed
+			}
+			err = opError(ErrAccept, s.network, s.address, err)
+			s.reportError(err)
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			if tempDelay == 0 {
+				tempDelay = 5 * time.Millisecond
+			} else {
+				tempDelay = min(2*tempDelay, time.Second)
+			}
+			time.Sleep(tempDelay)
+			continue
+		}
+		tempDelay = 0
+		s.logger.Infof("connected from: <%s>", s.peer(conn))
+		if !s.trackConn(conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(c net.Conn) {
+	defer s.wg.Done()
+	defer s.untrackConn(c)
+	defer c.Close()
+
+	read := s.reader(c)
+	for {
+		data, err := read()
+		if data != nil {
+			s.logger.Infof("received data from %s", s.peer(c))
+			s.logger.Debugf("received data from %s: %s", s.peer(c), data)
+			if !s.send(data) {
+				return
+			}
+		}
+		if err != nil {
+			if errors.Is(err, ErrFrameTooLarge) {
+				s.reportError(fmt.Errorf("drop connection from %s: %w: exceeds %d bytes", s.peer(c), err, s.opts.maxFrameSize))
+			} else if err != io.EOF && !s.shuttingDown() {
+				s.reportError(opError(ErrRead, s.network, s.address, err))
+			}
+			return
+		}
+	}
+}
+
+// reader returns a function reading the next message from c.
+func (s *Server) reader(c net.Conn) func() ([]byte, error) {
+	if s.opts.framer == nil {
+		return func() ([]byte, error) {
+			// must be in for loop, so channel will get the same slice
+			buf := make([]byte, s.mtu)
+			n, err := c.Read(buf)
+			if n == 0 {
+				return nil, err
+			}
+			return buf[:n], err
+		}
+	}
+
+	r := bufio.NewReaderSize(c, s.mtu)
+	return func() ([]byte, error) {
+		return s.opts.framer.ReadFrame(r, s.opts.maxFrameSize)
+	}
+}
+
+// send delivers data to the channel, it gives up when the server is forced to close.
+func (s *Server) send(data []byte) bool {
+	select {
+	case s.ch <- data:
+		return true
+	case <-s.quit:
+		return false
+	}
+}
+
+// peer returns the address used in logs for c, unix clients are usually unnamed.
+func (s *Server) peer(c net.Conn) string {
+	if s.network == "unix" {
+		return c.LocalAddr().String()
+	}
+	return c.RemoteAddr().String()
+}
+
+func (s *Server) reportError(err error) {
+	s.logger.Errorf("%s", err)
+	if s.opts.errorHandler != nil {
+		s.opts.errorHandler(err)
+	}
+}
+
+func (s *Server) trackConn(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrackConn(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, c)
+}
+
+func (s *Server) shuttingDown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shutdown
+}
+
+func (s *Server) closeConns() {
+	s.quitOnce.Do(func() { close(s.quit) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+// runContext runs s until ctx is done, then shuts it down.
+func runContext(ctx context.Context, s *Server) error {
+	if err := s.Listen(); err != nil {
+		s.Shutdown(context.Background())
+		return err
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Serve() }()
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		// Serve failed before ctx is done, stop the connections it has accepted
+		sctx, cancel := context.WithTimeout(context.Background(), s.opts.shutdownTimeout)
+		defer cancel()
+		s.Shutdown(sctx)
+		return err
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), s.opts.shutdownTimeout)
+	defer cancel()
+	err := s.Shutdown(sctx)
+	<-errCh
+	return err
+}
@@ -0,0 +1,227 @@
+package socket_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PengShaw/GoUtilsKit/logger"
+	"github.com/PengShaw/GoUtilsKit/socket"
+)
+
+func waitAddr(t *testing.T, s *socket.Server) net.Addr {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if addr := s.Addr(); addr != nil {
+			return addr
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("server is not listening")
+	return nil
+}
+
+func TestServerShutdown(t *testing.T) {
+	t.Run("test tcp server", func(t *testing.T) {
+		ch := make(chan []byte, 1)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, ch)
+		errCh := make(chan error, 1)
+		go func() { errCh <- s.ListenAndServe() }()
+
+		conn, err := net.Dial("tcp", waitAddr(t, s).String())
+		assert.NoError(t, err, "should not be an error")
+		_, err = conn.Write([]byte("hello"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, []byte("hello"), <-ch, "they should be equal")
+		conn.Close()
+
+		err = s.Shutdown(context.Background())
+		assert.NoError(t, err, "should not be an error")
+		assert.ErrorIs(t, <-errCh, socket.ErrServerClosed)
+		_, ok := <-ch
+		assert.False(t, ok, "channel should be closed")
+	})
+
+	t.Run("test idle connection", func(t *testing.T) {
+		ch := make(chan []byte, 1)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, ch)
+		go s.ListenAndServe()
+
+		conn, err := net.Dial("tcp", waitAddr(t, s).String())
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+		_, err = conn.Write([]byte("hello"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, []byte("hello"), <-ch, "they should be equal")
+
+		// the client keeps the connection open without sending anything
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		assert.NoError(t, s.Shutdown(ctx), "should not wait for the idle connection")
+	})
+
+	t.Run("test deadline with in-flight connection", func(t *testing.T) {
+		ch := make(chan []byte)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, ch)
+		go s.ListenAndServe()
+
+		conn, err := net.Dial("tcp", waitAddr(t, s).String())
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+		_, err = conn.Write([]byte("hello"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, []byte("hello"), <-ch, "they should be equal")
+		// nobody reads the channel any more, so the connection goroutine is blocked
+		_, err = conn.Write([]byte("world"))
+		assert.NoError(t, err, "should not be an error")
+		// let the server read it before the shutdown stops reading
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err = s.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		_, ok := <-ch
+		assert.False(t, ok, "channel should be closed")
+	})
+
+	t.Run("test udp server with context", func(t *testing.T) {
+		ch := make(chan []byte)
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- socket.RunUDPServerContext(ctx, "127.0.0.1:0", 1024, ch) }()
+		cancel()
+		assert.NoError(t, <-errCh, "should not be an error")
+		_, ok := <-ch
+		assert.False(t, ok, "channel should be closed")
+	})
+
+	t.Run("test shared channel", func(t *testing.T) {
+		ch := make(chan []byte, 1)
+		tcp := socket.NewTCPServer("127.0.0.1:0", 1024, ch, socket.WithSharedChannel())
+		udp := socket.NewUDPServer("127.0.0.1:0", 1024, ch, socket.WithSharedChannel())
+		go tcp.ListenAndServe()
+		go udp.ListenAndServe()
+		waitAddr(t, tcp)
+		conn, err := net.Dial("udp", waitAddr(t, udp).String())
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+
+		assert.NoError(t, tcp.Shutdown(context.Background()), "should not be an error")
+		_, err = conn.Write([]byte("hello"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, []byte("hello"), <-ch, "channel should stay open")
+		assert.NoError(t, udp.Shutdown(context.Background()), "should not be an error")
+		close(ch)
+	})
+}
+
+func TestServerErrors(t *testing.T) {
+	t.Run("test listen error", func(t *testing.T) {
+		ch := make(chan []byte)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, ch)
+		assert.NoError(t, s.Listen(), "should not be an error")
+		defer s.Shutdown(context.Background())
+
+		// the address is in use
+		err := socket.NewTCPServer(s.Addr().String(), 1024, ch).Listen()
+		assert.ErrorIs(t, err, socket.ErrListen)
+		assert.ErrorIs(t, socket.RunTCPServerContext(context.Background(), s.Addr().String(), 1024, make(chan []byte)), socket.ErrListen)
+	})
+
+	t.Run("test serve before listen", func(t *testing.T) {
+		s := socket.NewUDPServer("127.0.0.1:0", 1024, make(chan []byte))
+		assert.ErrorIs(t, s.Serve(), socket.ErrNotListening)
+	})
+
+	t.Run("test error handler", func(t *testing.T) {
+		errs := make(chan error, 1)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, make(chan []byte),
+			socket.WithFramer(socket.NewLineFramer()),
+			socket.WithMaxFrameSize(4),
+			socket.WithErrorHandler(func(err error) { errs <- err }))
+		assert.NoError(t, s.Listen(), "should not be an error")
+		go s.Serve()
+		defer s.Shutdown(context.Background())
+
+		conn, err := net.Dial("tcp", s.Addr().String())
+		assert.NoError(t, err, "should not be an error")
+		defer conn.Close()
+		_, err = conn.Write([]byte("too long line\n"))
+		assert.NoError(t, err, "should not be an error")
+		assert.ErrorIs(t, <-errs, socket.ErrFrameTooLarge)
+	})
+}
+
+func TestServerLogger(t *testing.T) {
+	t.Run("test injected logger", func(t *testing.T) {
+		l := logger.New(logger.LevelDebug)
+		var got bytes.Buffer
+		l.SetOutput(&got)
+		l.SetFlags(0)
+		ch := make(chan []byte, 1)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, ch, socket.WithLogger(l))
+		assert.NoError(t, s.Listen(), "should not be an error")
+		go s.Serve()
+
+		conn, err := net.Dial("tcp", s.Addr().String())
+		assert.NoError(t, err, "should not be an error")
+		_, err = conn.Write([]byte("hello"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, []byte("hello"), <-ch, "they should be equal")
+		conn.Close()
+		assert.NoError(t, s.Shutdown(context.Background()), "should not be an error")
+
+		assert.Contains(t, got.String(), "[INFO] listen: <"+s.Addr().String()+">\n")
+		assert.Contains(t, got.String(), "[DEBUG] received data from "+conn.LocalAddr().String()+": hello\n")
+	})
+
+	t.Run("test standard logger configuration", func(t *testing.T) {
+		std := logger.Default()
+		var got bytes.Buffer
+		std.SetOutput(&got)
+		std.SetFlags(0)
+		defer std.SetFlags(log.LstdFlags)
+		defer std.SetOutput(os.Stdout)
+		assert.NoError(t, logger.SetLevelSpec("info,socket.tcp=debug"), "should not be an error")
+		defer logger.SetLevelSpec("info")
+		// the standard logger is configured before the server is created
+		logger.SetRedactor(logger.NewRedactor(logger.RedactOptions{Rules: []logger.RedactRule{
+			{Name: "session", Pattern: regexp.MustCompile(`sid=\w+`), Replacement: "sid=<hidden>"},
+		}}))
+		defer logger.SetRedactor(nil)
+		ch := make(chan []byte, 1)
+		s := socket.NewTCPServer("127.0.0.1:0", 1024, ch)
+		assert.NoError(t, s.Listen(), "should not be an error")
+		go s.Serve()
+
+		conn, err := net.Dial("tcp", s.Addr().String())
+		assert.NoError(t, err, "should not be an error")
+		_, err = conn.Write([]byte("sid=42"))
+		assert.NoError(t, err, "should not be an error")
+		assert.Equal(t, []byte("sid=42"), <-ch, "they should be equal")
+		conn.Close()
+		assert.NoError(t, s.Shutdown(context.Background()), "should not be an error")
+
+		assert.Contains(t, got.String(), "[DEBUG] received data from "+conn.LocalAddr().String()+": sid=<hidden>\n")
+		assert.NotContains(t, got.String(), "sid=42", "should be redacted")
+	})
+
+	t.Run("test nop logger", func(t *testing.T) {
+		std := logger.Default()
+		var got bytes.Buffer
+		std.SetOutput(&got)
+		defer std.SetOutput(os.Stdout)
+		s := socket.NewUDPServer("127.0.0.1:0", 1024, make(chan []byte), socket.WithNopLogger())
+		assert.NoError(t, s.Listen(), "should not be an error")
+		assert.NoError(t, s.Shutdown(context.Background()), "should not be an error")
+		assert.Empty(t, got.String(), "should not log")
+	})
+}
@@ -1,134 +1,65 @@
 package socket
 
 import (
-	"io"
-	"net"
-
-	"github.com/PengShaw/GoUtilsKit/logger"
+	"context"
+	"fmt"
 )
 
-// RunSocketClient builds a socket connection, and send data to server
-func RunSocketClient(network, address string, ch <-chan []byte) {
-	logger.Debugf("run socket client to %s:%s", network, address)
-	conn, err := net.Dial(network, address)
-	if err != nil {
-		logger.Errorf("connect to %s:%s failed: %s", network, address, err)
-		return
+// RunSocketClient builds a socket connection, and send data to server until ch is closed.
+// It reconnects when the connection fails, see [Client]. It returns an error if
+// queued data is not sent in the shutdown timeout after ch is closed.
+// With [WithFramer], each data is written as one frame.
+func RunSocketClient(network, address string, ch <-chan []byte, opts ...Option) error {
+	c := NewClient(network, address, opts...)
+	for data := range ch {
+		c.Send(data)
 	}
-	defer conn.Close()
-	logger.Infof("dial: <%s>", conn.RemoteAddr().String())
 
-	for {
-		data := <-ch
-		_, err := conn.Write(data)
-		if err != nil {
-			logger.Errorf("send data to %s:%s failed: %s", network, address, err)
-			logger.Debugf("send data to %s:%s failed: %s: %s", network, address, err, data)
-		}
-		logger.Infof("send data to %s:%s success", network, address)
-		logger.Debugf("send data to %s:%s success: %s", network, address, data)
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.shutdownTimeout)
+	defer cancel()
+	if err := c.Close(ctx); err != nil {
+		err = opError(ErrWrite, network, address, fmt.Errorf("%d data dropped: %w", c.Queued(), err))
+		c.logger.Errorf("%s", err)
+		return err
 	}
+	return nil
 }
 
-// RunUDPServer listens an udp socket, and send received data to channel
-func RunUDPServer(address string, mtu int, ch chan<- []byte) {
-	logger.Debugf("run udp server at %s", address)
-	conn, err := net.ListenPacket("udp", address)
-	if err != nil {
-		logger.Errorf("listen udp:%s failed: %s", address, err)
-		return
-	}
-	defer conn.Close()
-	logger.Infof("listen: <%s>", conn.LocalAddr().String())
-
-	for {
-		// must be in for loop, so channel will get the same slice
-		buf := make([]byte, mtu)
-		n, addr, err := conn.ReadFrom(buf)
-		if err != nil {
-			logger.Errorf("listen udp:%s data failed: %s", address, err)
-			continue
-		}
-		logger.Infof("received data from %s", addr.String())
-		logger.Debugf("received data from %s: %s", addr.String(), buf)
-		ch <- buf[:n]
-	}
+// RunUDPServer listens an udp socket, and send received data to channel.
+// It returns an error wrapping [ErrListen] if it fails to listen.
+func RunUDPServer(address string, mtu int, ch chan<- []byte) error {
+	return NewUDPServer(address, mtu, ch).ListenAndServe()
 }
 
-// RunTCPServer listens an tcp socket, and send received data to channel
-func RunTCPServer(address string, mtu int, ch chan<- []byte) {
-	logger.Debugf("run tcp server at %s", address)
-	l, err := net.Listen("tcp", address)
-	if err != nil {
-		logger.Errorf("listen tcp:%s failed: %s", address, err)
-		return
-	}
-	defer l.Close()
-	logger.Infof("listen: <%s>", l.Addr().String())
-
-	for {
-		conn, err := l.Accept()
-		logger.Infof("connected from: <%s>", conn.RemoteAddr().String())
-		if err != nil {
-			logger.Errorf("connect tcp:%s failed: %s", address, err)
-			continue
-		}
+// RunTCPServer listens an tcp socket, and send received data to channel.
+// It returns an error wrapping [ErrListen] if it fails to listen.
+func RunTCPServer(address string, mtu int, ch chan<- []byte) error {
+	return NewTCPServer(address, mtu, ch).ListenAndServe()
+}
 
-		go func(c net.Conn, mtu int, ch chan<- []byte) {
-			defer c.Close()
-			for {
-				buf := make([]byte, mtu)
-				n, err := c.Read(buf)
-				if err != nil && err != io.EOF {
-					logger.Errorf("listen tcp:%s data failed: %s", address, err)
-					break
-				}
-				if err == io.EOF {
-					break
-				}
-				logger.Infof("received data from %s", c.RemoteAddr().String())
-				logger.Debugf("received data from %s: %s", c.RemoteAddr().String(), buf)
-				ch <- buf[:n]
-			}
-		}(conn, mtu, ch)
-	}
+// RunUnixServer listens an unix domain socket, and send received data to channel.
+// It returns an error wrapping [ErrListen] if it fails to listen.
+func RunUnixServer(address string, dataLength int, ch chan<- []byte) error {
+	return NewUnixServer(address, dataLength, ch).ListenAndServe()
 }
 
-// RunUnixServer listens an unix domain socket, and send received data to channel
-func RunUnixServer(address string, dataLength int, ch chan<- []byte) {
-	logger.Debugf("run udp server at %s", address)
-	l, err := net.Listen("unix", address)
-	if err != nil {
-		logger.Errorf("listen unix:%s failed: %s", address, err)
-		return
-	}
-	defer l.Close()
-	logger.Infof("listen: <%s>", l.Addr().String())
+// RunUDPServerContext listens an udp socket, and send received data to channel until ctx is done.
+// The server must be the only sender on the channel, which is closed when it
+// returns, unless [WithSharedChannel] is set.
+func RunUDPServerContext(ctx context.Context, address string, mtu int, ch chan<- []byte, opts ...Option) error {
+	return runContext(ctx, NewUDPServer(address, mtu, ch, opts...))
+}
 
-	for {
-		conn, err := l.Accept()
-		logger.Infof("connected: <%s>", l.Addr().String())
-		if err != nil {
-			logger.Errorf("connect unix:%s failed: %s", address, err)
-			continue
-		}
+// RunTCPServerContext listens an tcp socket, and send received data to channel until ctx is done.
+// The server must be the only sender on the channel, which is closed when it
+// returns, unless [WithSharedChannel] is set.
+func RunTCPServerContext(ctx context.Context, address string, mtu int, ch chan<- []byte, opts ...Option) error {
+	return runContext(ctx, NewTCPServer(address, mtu, ch, opts...))
+}
 
-		go func(c net.Conn, dataLength int, ch chan<- []byte) {
-			defer c.Close()
-			for {
-				buf := make([]byte, dataLength)
-				n, err := c.Read(buf)
-				if err != nil && err != io.EOF {
-					logger.Errorf("listen unix:%s data failed: %s", address, err)
-					break
-				}
-				if err == io.EOF {
-					break
-				}
-				logger.Infof("received data from %s", c.LocalAddr().String())
-				logger.Debugf("received data from %s: %s", c.LocalAddr().String(), buf)
-				ch <- buf[:n]
-			}
-		}(conn, dataLength, ch)
-	}
+// RunUnixServerContext listens an unix domain socket, and send received data to channel until ctx is done.
+// The server must be the only sender on the channel, which is closed when it
+// returns, unless [WithSharedChannel] is set.
+func RunUnixServerContext(ctx context.Context, address string, dataLength int, ch chan<- []byte, opts ...Option) error {
+	return runContext(ctx, NewUnixServer(address, dataLength, ch, opts...))
 }